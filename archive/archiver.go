@@ -0,0 +1,231 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/store"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Archiver saves the container logs of every finished job of the cron jobs
+// it watches, before successfulJobsHistoryLimit or failedJobsHistoryLimit
+// cleans the job's pods up
+type Archiver struct {
+	clientset *kubernetes.Clientset
+	cluster   string
+	cfg       pkg.ArchiveConfig
+	// logf reports what was archived and what went wrong
+	logf func(format string, args ...any)
+}
+
+func New(
+	clientset *kubernetes.Clientset,
+	cluster string,
+	cfg pkg.ArchiveConfig,
+	logf func(format string, args ...any),
+) Archiver {
+	return Archiver{
+		clientset: clientset,
+		cluster:   cluster,
+		cfg:       cfg,
+		logf:      logf,
+	}
+}
+
+// Run sweeps once every interval until ctx is done
+func (a Archiver) Run(ctx context.Context) {
+	for {
+		if err := a.Sweep(ctx); err != nil && ctx.Err() == nil {
+			a.logf("archive cron job logs: %v", err)
+		}
+
+		select {
+		case <-time.After(a.cfg.Interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Sweep archives the finished jobs of the watched cron jobs that aren't
+// archived yet, then prunes the runs past the retention settings
+func (a Archiver) Sweep(ctx context.Context) error {
+	for _, namespace := range a.namespaces() {
+		cronJobs, err := k8s.GetCronJobs(ctx, a.clientset, namespace)
+		if err != nil {
+			return fmt.Errorf("get cron jobs: %w", err)
+		}
+
+		for _, cronJob := range cronJobs {
+			if !a.watches(cronJob) {
+				continue
+			}
+
+			// one cron job failing, e.g. forbidden, doesn't stop the rest
+			if err := a.sweepCronJob(ctx, cronJob); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				a.logf("archive %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// namespaces lists the namespaces the patterns can match. a pattern whose
+// namespace is a pattern too matches in every namespace.
+func (a Archiver) namespaces() []string {
+	namespaces := []string{}
+	seen := map[string]bool{}
+
+	for _, p := range a.cfg.CronJobs {
+		namespace, _, _ := strings.Cut(p, "/")
+		if strings.ContainsAny(namespace, `*?[\`) {
+			return []string{""}
+		}
+
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces
+}
+
+func (a Archiver) watches(cronJob k8s.CronJob) bool {
+	for _, p := range a.cfg.CronJobs {
+		if ok, _ := path.Match(p, cronJob.Namespace+"/"+cronJob.Name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (a Archiver) sweepCronJob(ctx context.Context, cronJob k8s.CronJob) error {
+	jobs, err := k8s.GetJobs(ctx, a.clientset, cronJob.Namespace, cronJob.UID)
+	if err != nil {
+		return fmt.Errorf("get jobs: %w", err)
+	}
+
+	runs, err := store.LoadArchivedRuns(a.cluster, cronJob.Namespace, cronJob.Name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, job := range a.unarchived(jobs, runs, now) {
+		if err := a.archive(ctx, cronJob, job); err != nil {
+			return fmt.Errorf("archive job %s: %w", job.Name, err)
+		}
+	}
+
+	pruned, err := store.PruneArchivedRuns(
+		a.cluster,
+		cronJob.Namespace,
+		cronJob.Name,
+		a.cfg.MaxRuns,
+		a.cfg.MaxAge,
+		now,
+	)
+	for _, run := range pruned {
+		a.logf("pruned %s/%s", run.Job.Namespace, run.Job.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("prune archived runs: %w", err)
+	}
+
+	return nil
+}
+
+// unarchived returns the finished jobs that aren't archived yet and that
+// retention keeps once they are. the archived runs count towards MaxRuns
+// too, so a job the prune would drop right after isn't downloaded again on
+// every sweep.
+func (a Archiver) unarchived(jobs []k8s.Job, runs []store.ArchivedRun, now time.Time) []k8s.Job {
+	archived := map[string]bool{}
+	all := []k8s.Job{}
+
+	for _, run := range runs {
+		archived[run.Job.Name] = true
+		all = append(all, run.Job)
+	}
+
+	for _, job := range jobs {
+		if job.Finished && !archived[job.Name] {
+			all = append(all, job)
+		}
+	}
+
+	unarchived := []k8s.Job{}
+
+	for _, job := range store.Retained(all, a.cfg.MaxRuns, a.cfg.MaxAge, now) {
+		if !archived[job.Name] {
+			unarchived = append(unarchived, job)
+		}
+	}
+
+	return unarchived
+}
+
+func (a Archiver) archive(ctx context.Context, cronJob k8s.CronJob, job k8s.Job) error {
+	labelSelector := fmt.Sprintf("job-name=%s", job.Name)
+
+	containers, err := k8s.GetContainers(ctx, a.clientset, job.Namespace, labelSelector, "")
+	if err != nil {
+		return fmt.Errorf("get job containers: %w", err)
+	}
+
+	// pods that are gone already have nothing left to archive
+	containers = slices.DeleteFunc(containers, func(c k8s.Container) bool {
+		return c.Name == ""
+	})
+	if len(containers) == 0 {
+		return nil
+	}
+
+	logs := map[k8s.Container][]string{}
+
+	for _, c := range containers {
+		source := k8s.NewPodLogSource(
+			a.clientset,
+			a.cluster,
+			c,
+			job.StartTime,
+			k8s.LogOptions{Timestamps: true},
+		)
+
+		lines, err := source.Open(ctx)
+		if err != nil {
+			return fmt.Errorf("get logs of %s/%s: %w", c.Pod, c.Name, err)
+		}
+
+		logs[c] = lines
+	}
+
+	run := store.ArchivedRun{
+		Cluster:    a.cluster,
+		CronJob:    cronJob.Name,
+		Job:        job,
+		Containers: containers,
+		Archived:   time.Now(),
+	}
+
+	if err := store.SaveArchivedRun(run, logs); err != nil {
+		return err
+	}
+
+	a.logf("archived %s/%s", job.Namespace, job.Name)
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package archive
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/store"
+)
+
+func TestSweepRetention(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// job-n started n hours after the first, job-6 an hour before now
+	job := func(n int, finished bool) k8s.Job {
+		return k8s.Job{
+			Namespace: "default",
+			Name:      fmt.Sprintf("job-%d", n),
+			StartTime: now.Add(-time.Duration(7-n) * time.Hour),
+			Finished:  finished,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      pkg.ArchiveConfig
+		archived []k8s.Job
+		live     []k8s.Job
+		want     []string
+		kept     []string
+	}{
+		{
+			name:     "no limits",
+			archived: []k8s.Job{job(1, true)},
+			live:     []k8s.Job{job(1, true), job(2, true), job(3, false)},
+			want:     []string{"job-2"},
+			kept:     []string{"job-2", "job-1"},
+		},
+		{
+			name:     "older than the archived runs kept",
+			cfg:      pkg.ArchiveConfig{MaxRuns: 2},
+			archived: []k8s.Job{job(4, true), job(5, true)},
+			live:     []k8s.Job{job(3, true), job(4, true), job(5, true)},
+			kept:     []string{"job-5", "job-4"},
+		},
+		{
+			name:     "newer than the archived runs kept",
+			cfg:      pkg.ArchiveConfig{MaxRuns: 2},
+			archived: []k8s.Job{job(3, true), job(4, true)},
+			live:     []k8s.Job{job(3, true), job(4, true), job(5, true)},
+			want:     []string{"job-5"},
+			kept:     []string{"job-5", "job-4"},
+		},
+		{
+			name: "too many live jobs",
+			cfg:  pkg.ArchiveConfig{MaxRuns: 2},
+			live: []k8s.Job{job(2, true), job(4, true), job(3, true), job(5, false)},
+			want: []string{"job-4", "job-3"},
+			kept: []string{"job-4", "job-3"},
+		},
+		{
+			name:     "too old",
+			cfg:      pkg.ArchiveConfig{MaxAge: 3*time.Hour + 30*time.Minute},
+			archived: []k8s.Job{job(1, true)},
+			live:     []k8s.Job{job(2, true), job(4, true), job(6, true)},
+			want:     []string{"job-6", "job-4"},
+			kept:     []string{"job-6", "job-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+			a := New(nil, "test", tt.cfg, t.Logf)
+
+			for _, j := range tt.archived {
+				saveRun(t, j)
+			}
+
+			runs, err := store.LoadArchivedRuns("test", "default", "backup")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			unarchived := a.unarchived(tt.live, runs, now)
+			if got := jobNames(unarchived); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("archived %v, want %v", got, tt.want)
+			}
+
+			// what the sweep archived stays archived after the prune
+			for _, j := range unarchived {
+				saveRun(t, j)
+			}
+
+			pruned, err := store.PruneArchivedRuns("test", "default", "backup", tt.cfg.MaxRuns, tt.cfg.MaxAge, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, run := range pruned {
+				for _, j := range unarchived {
+					if run.Job.Name == j.Name {
+						t.Errorf("pruned %s right after archiving it", j.Name)
+					}
+				}
+			}
+
+			runs, err = store.LoadArchivedRuns("test", "default", "backup")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kept := []k8s.Job{}
+			for _, run := range runs {
+				kept = append(kept, run.Job)
+			}
+
+			if got := jobNames(kept); !reflect.DeepEqual(got, tt.kept) {
+				t.Errorf("kept %v, want %v", got, tt.kept)
+			}
+		})
+	}
+}
+
+func saveRun(t *testing.T, job k8s.Job) {
+	t.Helper()
+
+	c := k8s.Container{Namespace: job.Namespace, Pod: job.Name + "-abcde", Name: "main"}
+
+	run := store.ArchivedRun{
+		Cluster:    "test",
+		CronJob:    "backup",
+		Job:        job,
+		Containers: []k8s.Container{c},
+		Archived:   job.StartTime,
+	}
+
+	if err := store.SaveArchivedRun(run, map[k8s.Container][]string{c: {"done"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func jobNames(jobs []k8s.Job) []string {
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.Name)
+	}
+	return names
+}
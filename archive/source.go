@@ -0,0 +1,97 @@
+package archive
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/store"
+)
+
+// Source reads the archived logs of a container of a finished job
+type Source struct {
+	meta pkg.LogMeta
+	// since is the time Open and Follow start at, or zero for every line
+	since time.Time
+}
+
+func NewSource(
+	cluster string,
+	container k8s.Container,
+	start time.Time,
+	since time.Time,
+) Source {
+	return Source{
+		meta: pkg.LogMeta{
+			Cluster:   cluster,
+			Namespace: container.Namespace,
+			Pod:       container.Pod,
+			Container: container.Name,
+			Start:     start,
+		},
+		since: since,
+	}
+}
+
+func (s Source) Meta() pkg.LogMeta {
+	return s.meta
+}
+
+func (s Source) Open(ctx context.Context) ([]string, error) {
+	if !s.since.IsZero() {
+		return s.Seek(ctx, s.since)
+	}
+	return store.LoadArchivedLogs(s.meta.Cluster, s.container())
+}
+
+// Seek returns the lines from the first one logged at or after t. lines
+// were archived with their timestamps, so they're seeked by those.
+func (s Source) Seek(ctx context.Context, t time.Time) ([]string, error) {
+	lines, err := store.LoadArchivedLogs(s.meta.Cluster, s.container())
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range lines {
+		ts, _, _ := strings.Cut(line, " ")
+		if at, err := time.Parse(time.RFC3339Nano, ts); err == nil && !at.Before(t) {
+			return lines[i:], nil
+		}
+	}
+
+	return []string{}, nil
+}
+
+// Follow sends the lines Open reads, then ends, since the job is done
+// logging
+func (s Source) Follow(ctx context.Context) <-chan pkg.Result[string] {
+	lines, err := s.Open(ctx)
+
+	logsCh := make(chan pkg.Result[string], len(lines)+1)
+	defer close(logsCh)
+
+	if err != nil {
+		logsCh <- pkg.Result[string]{Err: err}
+		return logsCh
+	}
+
+	for _, line := range lines {
+		logsCh <- pkg.Result[string]{V: line}
+	}
+
+	return logsCh
+}
+
+func (s Source) Close() error {
+	return nil
+}
+
+func (s Source) container() k8s.Container {
+	return k8s.Container{
+		Namespace: s.meta.Namespace,
+		Pod:       s.meta.Pod,
+		Name:      s.meta.Container,
+	}
+}
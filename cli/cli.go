@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+	"k8s.io/client-go/kubernetes"
+)
+
+type command func(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	args []string,
+	w io.Writer,
+) error
+
+var commands = map[string]command{
+	"namespaces": runNamespaces,
+	"cronjobs":   runCronJobs,
+	"jobs":       runJobs,
+	"logs":       runLogs,
+}
+
+var usages = map[string]string{
+	"namespaces": "namespaces",
+	"cronjobs":   "cronjobs [namespace]",
+	"jobs":       "jobs <namespace>/<cronjob>",
+	"logs":       "logs <namespace>/<pod>/<container> [--follow] [--since 1h] [--tail 100]",
+}
+
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+func Run(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	args []string,
+	w io.Writer,
+) error {
+	if len(args) == 0 {
+		return errors.New("missing command")
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	if err := cmd(ctx, cfg, clientset, args[1:], w); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	return nil
+}
+
+func newFlagSet(name string, format *Format) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: log-viewer %s\n", usages[name])
+		fs.PrintDefaults()
+	}
+
+	*format = TextFormat
+	fs.Var(format, "output", "output format: text, json or ndjson")
+	fs.Var(format, "o", "shorthand for --output")
+
+	return fs
+}
+
+// parseArgs parses flags and returns between min and max positional
+// arguments. Flags are accepted before and after the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	positional := []string{}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < min || len(positional) > max {
+		fs.Usage()
+
+		if min == max {
+			return nil, fmt.Errorf(
+				"expected %d argument(s), got %d",
+				min,
+				len(positional),
+			)
+		}
+
+		return nil, fmt.Errorf(
+			"expected %d to %d argument(s), got %d",
+			min,
+			max,
+			len(positional),
+		)
+	}
+
+	return positional, nil
+}
+
+func splitPath(path string, n int, want string) ([]string, error) {
+	parts := strings.Split(path, "/")
+
+	if len(parts) != n || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("invalid %q: want %s", path, want)
+	}
+
+	return parts, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"k8s.io/client-go/kubernetes"
+)
+
+func runNamespaces(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	args []string,
+	w io.Writer,
+) error {
+	var format Format
+
+	fs := newFlagSet("namespaces", &format)
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	namespaces, err := k8s.GetNamespaces(ctx, clientset)
+	if err != nil {
+		return err
+	}
+
+	p := newPrinter(w, format)
+
+	for _, namespace := range namespaces {
+		v := struct {
+			Name string `json:"name"`
+		}{namespace}
+
+		if err := p.print(v, namespace); err != nil {
+			return err
+		}
+	}
+
+	return p.flush()
+}
+
+func runCronJobs(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	args []string,
+	w io.Writer,
+) error {
+	var format Format
+
+	fs := newFlagSet("cronjobs", &format)
+	positional, err := parseArgs(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	namespace := cfg.Namespace
+	if len(positional) > 0 {
+		namespace = positional[0]
+	}
+	if namespace == "" {
+		return errors.New("missing namespace and no default namespace configured")
+	}
+
+	cronJobs, err := k8s.GetCronJobs(ctx, clientset, namespace)
+	if err != nil {
+		return err
+	}
+
+	p := newPrinter(w, format)
+
+	for _, c := range cronJobs {
+		if err := p.print(
+			c,
+			c.Namespace,
+			c.Name,
+			formatTime(c.LastScheduleTime),
+		); err != nil {
+			return err
+		}
+	}
+
+	return p.flush()
+}
+
+func runJobs(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	args []string,
+	w io.Writer,
+) error {
+	var format Format
+
+	fs := newFlagSet("jobs", &format)
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	parts, err := splitPath(positional[0], 2, "<namespace>/<cronjob>")
+	if err != nil {
+		return err
+	}
+
+	cronJob, err := k8s.GetCronJob(ctx, clientset, parts[0], parts[1])
+	if err != nil {
+		return err
+	}
+
+	jobs, err := k8s.GetJobs(ctx, clientset, cronJob.Namespace, cronJob.UID)
+	if err != nil {
+		return err
+	}
+
+	p := newPrinter(w, format)
+
+	for _, j := range jobs {
+		if err := p.print(
+			j,
+			j.Namespace,
+			j.Name,
+			formatTime(j.StartTime),
+			formatTime(j.CompletionTime),
+			"failed="+strconv.Itoa(int(j.Failed)),
+			"succeeded="+strconv.Itoa(int(j.Succeeded)),
+		); err != nil {
+			return err
+		}
+	}
+
+	return p.flush()
+}
+
+// entryFlushDelay is how long a followed entry waits for more of its lines
+const entryFlushDelay = 250 * time.Millisecond
+
+type logLine struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Line      string `json:"line"`
+}
+
+func runLogs(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	args []string,
+	w io.Writer,
+) error {
+	var (
+		format Format
+		opts   k8s.LogOptions
+		follow bool
+	)
+
+	fs := newFlagSet("logs", &format)
+	fs.BoolVar(&follow, "follow", false, "stream new lines as they are written")
+	fs.BoolVar(&follow, "f", false, "shorthand for --follow")
+	fs.DurationVar(&opts.Since, "since", 0, "only return lines newer than a relative duration like 5s, 2m or 3h")
+	fs.Int64Var(&opts.TailLines, "tail", -1, "number of recent lines to show; -1 or 0 shows all, unlike kubectl's --tail 0")
+
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	if follow && format == JSONFormat {
+		return errors.New("--follow can't be used with json output, use ndjson instead")
+	}
+
+	parts, err := splitPath(positional[0], 3, "<namespace>/<pod>/<container>")
+	if err != nil {
+		return err
+	}
+
+	namespace, pod, container := parts[0], parts[1], parts[2]
+
+	multiline, err := pkg.NewMultiline(cfg.Multiline)
+	if err != nil {
+		return err
+	}
+
+	p := newPrinter(w, format)
+
+	printLine := func(line string) error {
+		v := logLine{
+			Namespace: namespace,
+			Pod:       pod,
+			Container: container,
+			Line:      line,
+		}
+		return p.printLine(v, line)
+	}
+
+	source := k8s.NewPodLogSource(
+		clientset,
+		"",
+		k8s.Container{Namespace: namespace, Pod: pod, Name: container},
+		time.Time{},
+		opts,
+	)
+	defer source.Close()
+
+	if !follow {
+		logs, err := source.Open(ctx)
+		if err != nil {
+			return err
+		}
+
+		// a multi-line entry, e.g. a stack trace, is a single record
+		for _, entry := range multiline.Group(logs) {
+			if err := printLine(pkg.JoinEntry(entry)); err != nil {
+				return err
+			}
+		}
+
+		return p.flush()
+	}
+
+	logsCh := source.Follow(ctx)
+
+	// an entry is held until a line starts the next one, or the stream
+	// goes quiet
+	entry := []string{}
+
+	printEntry := func() error {
+		if len(entry) == 0 {
+			return nil
+		}
+		if err := printLine(pkg.JoinEntry(entry)); err != nil {
+			return err
+		}
+		entry = entry[:0]
+		// flush every entry so followers (grep, tee, ...) see it immediately
+		return p.flush()
+	}
+
+	for {
+		var quiet <-chan time.Time
+		if len(entry) > 0 {
+			quiet = time.After(entryFlushDelay)
+		}
+
+		select {
+		case r, ok := <-logsCh:
+			if !ok {
+				return printEntry()
+			}
+			if r.Err != nil {
+				return fmt.Errorf("stream pod logs: %w", r.Err)
+			}
+
+			if multiline.Continues(entry, r.V) {
+				entry = append(entry, r.V)
+				continue
+			}
+			if err := printEntry(); err != nil {
+				return err
+			}
+			if strings.TrimSpace(r.V) != "" {
+				entry = append(entry, r.V)
+			}
+		case <-quiet:
+			if err := printEntry(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type Format string
+
+const (
+	TextFormat   Format = "text"
+	JSONFormat   Format = "json"
+	NDJSONFormat Format = "ndjson"
+)
+
+func (f *Format) String() string {
+	return string(*f)
+}
+
+func (f *Format) Set(s string) error {
+	switch Format(s) {
+	case TextFormat, JSONFormat, NDJSONFormat:
+		*f = Format(s)
+		return nil
+	default:
+		return fmt.Errorf(
+			"unknown output format %q (want %s, %s or %s)",
+			s,
+			TextFormat,
+			JSONFormat,
+			NDJSONFormat,
+		)
+	}
+}
+
+// printer writes one record at a time in the selected format. json output is
+// buffered until flush, since it has to be written as a single array.
+type printer struct {
+	format Format
+	w      io.Writer
+	tw     *tabwriter.Writer
+	items  []any
+}
+
+func newPrinter(w io.Writer, format Format) *printer {
+	return &printer{
+		format: format,
+		w:      w,
+		tw:     tabwriter.NewWriter(w, 0, 4, 2, ' ', 0),
+	}
+}
+
+func (p *printer) print(v any, columns ...string) error {
+	switch p.format {
+	case JSONFormat:
+		p.items = append(p.items, v)
+		return nil
+	case NDJSONFormat:
+		return json.NewEncoder(p.w).Encode(v)
+	default:
+		_, err := fmt.Fprintln(p.tw, strings.Join(columns, "\t"))
+		return err
+	}
+}
+
+// printLine prints a record that's a single line of text as it is. a log
+// line isn't a row of columns, so the tabs in it aren't aligned, and it's
+// written right away.
+func (p *printer) printLine(v any, line string) error {
+	switch p.format {
+	case JSONFormat, NDJSONFormat:
+		return p.print(v)
+	default:
+		_, err := fmt.Fprintln(p.w, line)
+		return err
+	}
+}
+
+func (p *printer) flush() error {
+	switch p.format {
+	case JSONFormat:
+		items := p.items
+		if items == nil {
+			items = []any{}
+		}
+
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(items)
+	case NDJSONFormat:
+		return nil
+	default:
+		return p.tw.Flush()
+	}
+}
@@ -0,0 +1,183 @@
+package diff
+
+import "slices"
+
+type Op int
+
+const (
+	Equal Op = iota
+	// Added lines are only in the right side
+	Added
+	// Removed lines are only in the left side
+	Removed
+)
+
+// maxEdits bounds the work spent on sides that barely match. past it, the
+// rest of the sides are reported as replaced wholesale.
+const maxEdits = 2000
+
+// Line is a line of the diff. Left and Right are its line numbers on each
+// side, from 1, and zero on the side it isn't on.
+type Line struct {
+	Op    Op
+	Text  string
+	Left  int
+	Right int
+}
+
+// Side is one of the logs being compared
+type Side struct {
+	Lines      []string
+	Normalizer Normalizer
+}
+
+func (s Side) keys() []string {
+	keys := make([]string, len(s.Lines))
+	for i, line := range s.Lines {
+		keys[i] = s.Normalizer.Normalize(line)
+	}
+	return keys
+}
+
+// Compare lines up the sides by their normalized lines, keeping as many in
+// common as it can. the text of a line on both sides is the right side's.
+func Compare(left Side, right Side) []Line {
+	ops := script(left.keys(), right.keys())
+	lines := make([]Line, 0, len(ops))
+
+	l, r := 0, 0
+
+	for _, op := range ops {
+		switch op {
+		case Equal:
+			l++
+			r++
+			lines = append(lines, Line{Op: op, Text: right.Lines[r-1], Left: l, Right: r})
+		case Added:
+			r++
+			lines = append(lines, Line{Op: op, Text: right.Lines[r-1], Right: r})
+		case Removed:
+			l++
+			lines = append(lines, Line{Op: op, Text: left.Lines[l-1], Left: l})
+		}
+	}
+
+	return lines
+}
+
+// FirstChange is the index of the first line that isn't on both sides, or
+// -1 when the sides match
+func FirstChange(lines []Line) int {
+	return slices.IndexFunc(lines, func(l Line) bool { return l.Op != Equal })
+}
+
+// script is the shortest edit script from a to b, found with Myers'
+// algorithm after the common prefix and suffix are set aside
+func script(a []string, b []string) []Op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix &&
+		suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := repeat(Equal, prefix)
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	return append(ops, repeat(Equal, suffix)...)
+}
+
+func repeat(op Op, n int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		ops[i] = op
+	}
+	return ops
+}
+
+func myers(a []string, b []string) []Op {
+	n, m := len(a), len(b)
+	total := n + m
+	if total == 0 {
+		return nil
+	}
+
+	// v has the furthest x reached on each diagonal k = x - y, at v[total+k]
+	v := make([]int, 2*total+2)
+	// trace keeps v[-d..d] as it was before each step d, to walk back on
+	trace := [][]int{}
+
+	for d := 0; d <= total && d <= maxEdits; d++ {
+		trace = append(trace, slices.Clone(v[total-d:total+d+1]))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[total+k-1] < v[total+k+1]) {
+				x = v[total+k+1]
+			} else {
+				x = v[total+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[total+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+
+	return append(repeat(Removed, n), repeat(Added, m)...)
+}
+
+func backtrack(trace [][]int, x int, y int) []Op {
+	ops := []Op{}
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, Equal)
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, Added)
+			} else {
+				ops = append(ops, Removed)
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	slices.Reverse(ops)
+
+	return ops
+}
@@ -0,0 +1,239 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  []string
+		right []string
+		want  []Line
+	}{
+		{
+			name: "both empty",
+			want: []Line{},
+		},
+		{
+			name:  "identical",
+			left:  []string{"a", "b", "c"},
+			right: []string{"a", "b", "c"},
+			want: []Line{
+				{Op: Equal, Text: "a", Left: 1, Right: 1},
+				{Op: Equal, Text: "b", Left: 2, Right: 2},
+				{Op: Equal, Text: "c", Left: 3, Right: 3},
+			},
+		},
+		{
+			name:  "left empty",
+			right: []string{"a", "b"},
+			want: []Line{
+				{Op: Added, Text: "a", Right: 1},
+				{Op: Added, Text: "b", Right: 2},
+			},
+		},
+		{
+			name: "right empty",
+			left: []string{"a", "b"},
+			want: []Line{
+				{Op: Removed, Text: "a", Left: 1},
+				{Op: Removed, Text: "b", Left: 2},
+			},
+		},
+		{
+			name:  "disjoint",
+			left:  []string{"a", "b"},
+			right: []string{"c", "d"},
+			want: []Line{
+				{Op: Removed, Text: "a", Left: 1},
+				{Op: Removed, Text: "b", Left: 2},
+				{Op: Added, Text: "c", Right: 1},
+				{Op: Added, Text: "d", Right: 2},
+			},
+		},
+		{
+			name:  "changed prefix",
+			left:  []string{"x", "b", "c"},
+			right: []string{"y", "b", "c"},
+			want: []Line{
+				{Op: Removed, Text: "x", Left: 1},
+				{Op: Added, Text: "y", Right: 1},
+				{Op: Equal, Text: "b", Left: 2, Right: 2},
+				{Op: Equal, Text: "c", Left: 3, Right: 3},
+			},
+		},
+		{
+			name:  "changed suffix",
+			left:  []string{"a", "b", "x"},
+			right: []string{"a", "b", "y", "z"},
+			want: []Line{
+				{Op: Equal, Text: "a", Left: 1, Right: 1},
+				{Op: Equal, Text: "b", Left: 2, Right: 2},
+				{Op: Removed, Text: "x", Left: 3},
+				{Op: Added, Text: "y", Right: 3},
+				{Op: Added, Text: "z", Right: 4},
+			},
+		},
+		{
+			name:  "inserted and removed in the middle",
+			left:  []string{"a", "b", "c", "d"},
+			right: []string{"a", "c", "x", "d"},
+			want: []Line{
+				{Op: Equal, Text: "a", Left: 1, Right: 1},
+				{Op: Removed, Text: "b", Left: 2},
+				{Op: Equal, Text: "c", Left: 3, Right: 2},
+				{Op: Added, Text: "x", Right: 3},
+				{Op: Equal, Text: "d", Left: 4, Right: 4},
+			},
+		},
+		{
+			name:  "normalized lines match",
+			left:  []string{"2024-01-01T10:00:00Z started", "done in 1.5s"},
+			right: []string{"2024-01-02T10:00:00Z started", "done in 2s"},
+			want: []Line{
+				{Op: Equal, Text: "2024-01-02T10:00:00Z started", Left: 1, Right: 1},
+				{Op: Equal, Text: "done in 2s", Left: 2, Right: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(Side{Lines: tt.left}, Side{Lines: tt.right})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     []string
+		b     []string
+		edits int
+	}{
+		{name: "identical", a: lines("a", 5), b: lines("a", 5), edits: 0},
+		{name: "disjoint", a: lines("a", 5), b: lines("b", 4), edits: 9},
+		{name: "one changed", a: []string{"a", "b", "c", "d"}, b: []string{"a", "x", "c", "d"}, edits: 2},
+		{name: "moved line", a: []string{"a", "b", "c", "d"}, b: []string{"b", "c", "d", "a"}, edits: 2},
+		{
+			name:  "interleaved",
+			a:     []string{"a", "b", "c", "a", "b", "b", "a"},
+			b:     []string{"c", "b", "a", "b", "a", "c"},
+			edits: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := script(tt.a, tt.b)
+			checkScript(t, ops, tt.a, tt.b)
+
+			if got := edits(ops); got != tt.edits {
+				t.Errorf("got %d edits, want %d", got, tt.edits)
+			}
+		})
+	}
+}
+
+func TestScriptMaxEdits(t *testing.T) {
+	// every third line matches, which takes more than maxEdits edits to
+	// line up, so everything between the common prefix and suffix is
+	// replaced
+	a, b := []string{"start"}, []string{"start"}
+	for i := range 3000 {
+		if i%3 == 1 {
+			a = append(a, fmt.Sprint("same ", i))
+			b = append(b, fmt.Sprint("same ", i))
+			continue
+		}
+		a = append(a, fmt.Sprint("left ", i))
+		b = append(b, fmt.Sprint("right ", i))
+	}
+	a, b = append(a, "end"), append(b, "end")
+
+	ops := script(a, b)
+	checkScript(t, ops, a, b)
+
+	want := []Op{Equal}
+	want = append(want, repeat(Removed, 3000)...)
+	want = append(want, repeat(Added, 3000)...)
+	want = append(want, Equal)
+
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("got %d ops with %d edits, want the middle replaced", len(ops), edits(ops))
+	}
+}
+
+func TestFirstChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []Line
+		want  int
+	}{
+		{name: "empty", want: -1},
+		{name: "no change", lines: []Line{{Op: Equal}, {Op: Equal}}, want: -1},
+		{name: "added", lines: []Line{{Op: Equal}, {Op: Added}}, want: 1},
+		{name: "removed first", lines: []Line{{Op: Removed}, {Op: Equal}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FirstChange(tt.lines); got != tt.want {
+				t.Errorf("FirstChange() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkScript checks that ops turns a into b
+func checkScript(t *testing.T, ops []Op, a []string, b []string) {
+	t.Helper()
+
+	got := []string{}
+	x, y := 0, 0
+
+	for _, op := range ops {
+		switch op {
+		case Equal:
+			if x >= len(a) || y >= len(b) || a[x] != b[y] {
+				t.Fatalf("op %d keeps line %d of a as line %d of b, which differ", len(got), x, y)
+			}
+			got = append(got, a[x])
+			x++
+			y++
+		case Added:
+			got = append(got, b[y])
+			y++
+		case Removed:
+			x++
+		}
+	}
+
+	if x != len(a) || !reflect.DeepEqual(got, b) {
+		t.Fatalf("script doesn't turn a into b")
+	}
+}
+
+func edits(ops []Op) int {
+	n := 0
+	for _, op := range ops {
+		if op != Equal {
+			n++
+		}
+	}
+	return n
+}
+
+func lines(prefix string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprint(prefix, i)
+	}
+	return lines
+}
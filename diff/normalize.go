@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// volatile matches what differs between runs of a job even when they log
+// the same thing, in the order it's replaced
+var volatile = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	{
+		regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`),
+		"<time>",
+	},
+	{
+		regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(?:[.,]\d+)?\b`),
+		"<time>",
+	},
+	{
+		regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`),
+		"<uuid>",
+	},
+	{
+		regexp.MustCompile(`\b(?:\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h))+\b`),
+		"<duration>",
+	},
+	// pods of deployments are named <deployment>-<replica set hash>-<hash>,
+	// in the alphabet kubernetes generates names with
+	{
+		regexp.MustCompile(`\b([a-z0-9](?:[-a-z0-9]*[a-z0-9])?)-[bcdfghjklmnpqrstvwxz2456789]{6,10}-[bcdfghjklmnpqrstvwxz2456789]{5}\b`),
+		"$1-<hash>",
+	},
+}
+
+// Normalizer reduces a line to what should match between runs of a job
+type Normalizer struct {
+	names *strings.Replacer
+}
+
+// NewNormalizer also replaces names given in old, new pairs, e.g. the job's
+// own name and its pods', which always differ between runs. names are
+// tried in the order given, so pass longer ones first, e.g. a pod's name
+// before the job name it starts with.
+func NewNormalizer(names ...string) Normalizer {
+	return Normalizer{names: strings.NewReplacer(names...)}
+}
+
+func (n Normalizer) Normalize(line string) string {
+	if n.names != nil {
+		line = n.names.Replace(line)
+	}
+
+	for _, v := range volatile {
+		line = v.re.ReplaceAllString(line, v.with)
+	}
+
+	return line
+}
@@ -0,0 +1,99 @@
+package diff
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		line  string
+		want  string
+	}{
+		{
+			name: "plain line",
+			line: "connected to db",
+			want: "connected to db",
+		},
+		{
+			name: "rfc3339 time",
+			line: "2024-01-02T03:04:05.123456789Z started",
+			want: "<time> started",
+		},
+		{
+			name: "time with offset",
+			line: "at 2024-01-02 03:04:05+01:00 done",
+			want: "at <time> done",
+		},
+		{
+			name: "time of day",
+			line: "[03:04:05,123] tick",
+			want: "[<time>] tick",
+		},
+		{
+			name: "uuid",
+			line: "request 0F8FAD5B-D9CB-469F-A165-70867728950E failed",
+			want: "request <uuid> failed",
+		},
+		{
+			name: "durations",
+			line: "took 1.5s, then 250ms, then 1h2m3s",
+			want: "took <duration>, then <duration>, then <duration>",
+		},
+		{
+			name: "counts aren't durations",
+			line: "processed 42 rows in 3 batches",
+			want: "processed 42 rows in 3 batches",
+		},
+		{
+			name: "pod hash",
+			line: "scheduled api-7d4b9c8f6-x2x5z on node-1",
+			want: "scheduled api-<hash> on node-1",
+		},
+		{
+			name: "not a pod hash",
+			line: "using api-version-2 from image-abc",
+			want: "using api-version-2 from image-abc",
+		},
+		{
+			name:  "names",
+			names: []string{"backup-28401234", "<job>"},
+			line:  "job backup-28401234 done",
+			want:  "job <job> done",
+		},
+		{
+			name:  "longer name first",
+			names: []string{"backup-28401234-q7k2n", "<pod>", "backup-28401234", "<job>"},
+			line:  "pod backup-28401234-q7k2n of backup-28401234",
+			want:  "pod <pod> of <job>",
+		},
+		{
+			name:  "shorter name first",
+			names: []string{"backup-28401234", "<job>", "backup-28401234-q7k2n", "<pod>"},
+			line:  "pod backup-28401234-q7k2n",
+			want:  "pod <job>-q7k2n",
+		},
+		{
+			name:  "names before volatile parts",
+			names: []string{"backup-28401234", "<job>"},
+			line:  "2024-01-02T03:04:05Z backup-28401234 took 2s",
+			want:  "<time> <job> took <duration>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNormalizer(tt.names...)
+			if got := n.Normalize(tt.line); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeZero(t *testing.T) {
+	var n Normalizer
+
+	if got, want := n.Normalize("2024-01-02T03:04:05Z started"), "<time> started"; got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,276 @@
+package dispatch
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+const (
+	DefaultWorkers = 4
+	recentSize     = 20
+)
+
+type Handler func(ctx context.Context, msg tea.Msg) ([]list.Item, error)
+
+type waiter struct {
+	id   tui.RequestID
+	ctx  context.Context
+	stop func() bool
+}
+
+type job struct {
+	msg tea.Msg
+	// coalesce says msg is a key of pending. msgs of unhashable types
+	// never are, since looking them up panics.
+	coalesce bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	waiters  []waiter
+	entry    tui.QueueEntry
+}
+
+// Dispatcher runs requests on a fixed number of workers. Identical requests
+// that are queued or running at the same time share a single fetch.
+type Dispatcher struct {
+	workers int
+	handler Handler
+	wake    chan struct{}
+
+	mu      sync.Mutex
+	ctx     context.Context
+	send    func(tea.Msg)
+	queue   []*job
+	running map[*job]struct{}
+	pending map[tea.Msg]*job
+	stats   tui.QueueStats
+}
+
+func New(workers int, handler Handler) *Dispatcher {
+	return &Dispatcher{
+		workers: workers,
+		handler: handler,
+		wake:    make(chan struct{}, 1),
+		ctx:     context.Background(),
+		running: map[*job]struct{}{},
+		pending: map[tea.Msg]*job{},
+	}
+}
+
+// Run starts the workers and blocks until ctx is done and every running
+// request has returned. Responses are delivered with send.
+func (d *Dispatcher) Run(ctx context.Context, send func(tea.Msg)) {
+	d.mu.Lock()
+	d.ctx = ctx
+	d.send = send
+	d.mu.Unlock()
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+
+	d.signal()
+	wg.Wait()
+}
+
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) Submit(req tui.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := reflect.TypeOf(req.Msg)
+	key, coalesce := req.Msg, t != nil && t.Comparable()
+
+	if coalesce {
+		if j, ok := d.pending[key]; ok {
+			d.addWaiter(j, req)
+			d.stats.Coalesced++
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(d.ctx)
+
+	j := &job{
+		msg:      req.Msg,
+		coalesce: coalesce,
+		ctx:      ctx,
+		cancel:   cancel,
+		entry: tui.QueueEntry{
+			Msg:    req.Msg,
+			Queued: time.Now(),
+		},
+	}
+
+	d.addWaiter(j, req)
+	d.queue = append(d.queue, j)
+
+	if coalesce {
+		d.pending[key] = j
+	}
+
+	d.signal()
+}
+
+// addWaiter attaches a request to a job. The job is cancelled once every
+// request waiting on it has been cancelled.
+func (d *Dispatcher) addWaiter(j *job, req tui.Request) {
+	w := waiter{id: req.ID, ctx: req.Ctx}
+
+	w.stop = context.AfterFunc(req.Ctx, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		for _, w := range j.waiters {
+			if w.ctx.Err() == nil {
+				return
+			}
+		}
+
+		d.release(j)
+		j.cancel()
+	})
+
+	j.waiters = append(j.waiters, w)
+	j.entry.RequestIDs = append(j.entry.RequestIDs, req.ID)
+}
+
+// release stops new requests from coalescing onto j
+func (d *Dispatcher) release(j *job) {
+	if j.coalesce && d.pending[j.msg] == j {
+		delete(d.pending, j.msg)
+	}
+}
+
+func (d *Dispatcher) next() *job {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for len(d.queue) > 0 {
+		j := d.queue[0]
+		d.queue = d.queue[1:]
+
+		if j.ctx.Err() != nil {
+			d.finish(j, nil)
+			continue
+		}
+
+		j.entry.Started = time.Now()
+		d.running[j] = struct{}{}
+
+		// let another idle worker pick up the rest of the queue
+		if len(d.queue) > 0 {
+			d.signal()
+		}
+
+		return j
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		if j := d.next(); j != nil {
+			d.run(j)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		}
+	}
+}
+
+func (d *Dispatcher) run(j *job) {
+	items, err := d.handler(j.ctx, j.msg)
+
+	d.mu.Lock()
+	delete(d.running, j)
+	d.release(j)
+	waiters := j.waiters
+	d.finish(j, err)
+	d.mu.Unlock()
+
+	for _, w := range waiters {
+		w.stop()
+
+		// the view that made the request is gone, so nobody wants the response
+		if w.ctx.Err() != nil {
+			log.Printf("request %d (%T) cancelled\n", w.id, j.msg)
+			continue
+		}
+
+		if err != nil {
+			log.Printf("handle request %d: %v\n", w.id, err)
+			d.send(tui.ErrorMsg{RequestID: w.id, Msg: j.msg, Err: err})
+			continue
+		}
+
+		d.send(tui.ItemsMsg{RequestID: w.id, Items: items})
+	}
+
+	j.cancel()
+}
+
+func (d *Dispatcher) finish(j *job, err error) {
+	j.entry.Finished = time.Now()
+	j.entry.Err = err
+	j.entry.Cancelled = j.ctx.Err() != nil
+
+	if j.entry.Cancelled {
+		d.stats.Cancelled++
+	} else {
+		d.stats.Completed++
+	}
+
+	d.stats.Recent = append([]tui.QueueEntry{j.entry}, d.stats.Recent...)
+	if len(d.stats.Recent) > recentSize {
+		d.stats.Recent = d.stats.Recent[:recentSize]
+	}
+}
+
+func (d *Dispatcher) Stats() tui.QueueStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := d.stats
+	stats.Workers = d.workers
+	stats.Recent = append([]tui.QueueEntry{}, d.stats.Recent...)
+
+	for _, j := range d.queue {
+		stats.Queued = append(stats.Queued, j.entry)
+	}
+
+	for j := range d.running {
+		stats.Running = append(stats.Running, j.entry)
+	}
+
+	slices.SortFunc(stats.Running, func(a, b tui.QueueEntry) int {
+		return a.Started.Compare(b.Started)
+	})
+
+	return stats
+}
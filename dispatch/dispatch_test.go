@@ -0,0 +1,65 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+func TestSubmitCoalescing(t *testing.T) {
+	type hashable struct{ name string }
+
+	tests := []struct {
+		name      string
+		msg       tea.Msg
+		coalesced int
+	}{
+		{name: "hashable", msg: hashable{name: "pods"}, coalesced: 1},
+		{name: "unhashable", msg: []string{"pods"}, coalesced: 0},
+		{name: "nil", msg: nil, coalesced: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(1, func(ctx context.Context, msg tea.Msg) ([]list.Item, error) {
+				return nil, nil
+			})
+
+			// both are queued before any worker runs, so they can coalesce
+			d.Submit(tui.Request{ID: 1, Ctx: context.Background(), Msg: tt.msg})
+			d.Submit(tui.Request{ID: 2, Ctx: context.Background(), Msg: tt.msg})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			responses := make(chan tea.Msg, 2)
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				d.Run(ctx, func(msg tea.Msg) { responses <- msg })
+			}()
+
+			ids := map[tui.RequestID]bool{}
+			for range 2 {
+				msg, ok := (<-responses).(tui.ItemsMsg)
+				if !ok {
+					t.Fatalf("got %T, want tui.ItemsMsg", msg)
+				}
+				ids[msg.RequestID] = true
+			}
+
+			cancel()
+			<-done
+
+			if !ids[1] || !ids[2] {
+				t.Errorf("got responses to %v, want 1 and 2", ids)
+			}
+
+			if got := d.Stats().Coalesced; got != tt.coalesced {
+				t.Errorf("coalesced %d requests, want %d", got, tt.coalesced)
+			}
+		})
+	}
+}
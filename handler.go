@@ -0,0 +1,359 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/archive"
+	"github.com/joshuasprow/log-viewer/diff"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/local"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/session"
+	"github.com/joshuasprow/log-viewer/store"
+	"github.com/joshuasprow/log-viewer/tui"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type handler struct {
+	cfg         pkg.Config
+	clientset   *kubernetes.Clientset
+	kubeContext k8s.KubeContext
+	logOptions  k8s.LogOptions
+	// files are the local log files and directories to list
+	files []string
+	// stdin holds the lines piped in, if any
+	stdin *local.Pipe
+	// recorder records the lines of every source opened, if set
+	recorder *session.Recorder
+	// replay is the session being replayed, at speed
+	replay *session.Session
+	speed  float64
+	// archiver archives the logs of finished cron jobs while the viewer
+	// runs, if any are configured
+	archiver *archive.Archiver
+}
+
+func (h handler) loadItems(ctx context.Context, msg tea.Msg) ([]list.Item, error) {
+	items, err := h.load(ctx, msg)
+	if err != nil {
+		return nil, k8s.DiagnoseForbidden(ctx, h.clientset, err, requiredPermissions(msg))
+	}
+
+	return items, nil
+}
+
+// Pod reads the logs of a container from the pod log API
+func (h handler) Pod(container k8s.Container, start time.Time, since time.Time) pkg.LogSource {
+	opts := h.logOptions
+	opts.SinceTime = since
+
+	return h.record(k8s.NewPodLogSource(h.clientset, h.kubeContext.Cluster, container, start, opts))
+}
+
+// File reads a local log file, or the lines piped on stdin
+func (h handler) File(path string) pkg.LogSource {
+	if path == local.StdinPath && h.stdin != nil {
+		return h.record(h.stdin)
+	}
+	return h.record(local.NewFileSource(path))
+}
+
+// Replay plays a stream of the recorded session back
+func (h handler) Replay(stream int, since time.Time) pkg.LogSource {
+	return h.replay.Replay(stream, since, h.speed)
+}
+
+// Archived reads the archived logs of a container of a finished job
+func (h handler) Archived(container k8s.Container, start time.Time, since time.Time) pkg.LogSource {
+	return h.record(archive.NewSource(h.kubeContext.Cluster, container, start, since))
+}
+
+func (h handler) record(source pkg.LogSource) pkg.LogSource {
+	if h.recorder == nil {
+		return source
+	}
+	return h.recorder.Record(source)
+}
+
+// readLogs reads the latest lines of source, or the ones since a time
+func readLogs(ctx context.Context, source pkg.LogSource, since time.Time) ([]string, error) {
+	defer source.Close()
+
+	if since.IsZero() {
+		return source.Open(ctx)
+	}
+	return source.Seek(ctx, since)
+}
+
+func (h handler) load(ctx context.Context, msg tea.Msg) ([]list.Item, error) {
+	switch msg := msg.(type) {
+	case tui.NamespacesViewMsg:
+		namespaces, err := k8s.GetNamespaces(ctx, h.clientset)
+		if k8s.Classify(err) == k8s.ForbiddenError {
+			log.Printf("list namespaces forbidden, using fallback: %v\n", err)
+			return h.fallbackNamespaces(), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get namespaces: %w", err)
+		}
+
+		namespaces = slices.DeleteFunc(namespaces, h.hidden)
+
+		return tui.WrapNamespaces(namespaces), nil
+	case tui.ApisViewMsg:
+		return tui.GetApis(), nil
+	case tui.ContainersViewMsg:
+		containers, err := k8s.GetContainers(ctx, h.clientset, msg.Namespace, msg.LabelSelector, msg.FieldSelector)
+		if err != nil {
+			return nil, fmt.Errorf("get containers: %w", err)
+		}
+
+		containers = slices.DeleteFunc(containers, func(c k8s.Container) bool {
+			return h.hidden(c.Namespace)
+		})
+
+		return tui.WrapContainers(containers, msg.Namespace == metav1.NamespaceAll), nil
+	case tui.CronJobsViewMsg:
+		cronJobs, err := k8s.GetCronJobs(ctx, h.clientset, msg.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("get cron jobs: %w", err)
+		}
+
+		cronJobs = slices.DeleteFunc(cronJobs, func(c k8s.CronJob) bool {
+			return h.hidden(c.Namespace)
+		})
+
+		return tui.WrapCronJobs(cronJobs, msg.Namespace == metav1.NamespaceAll), nil
+	case tui.CronJobJobsViewMsg:
+		jobs, err := k8s.GetJobs(ctx, h.clientset, msg.CronJob.Namespace, msg.CronJob.UID)
+		if err != nil {
+			return nil, fmt.Errorf("get jobs: %w", err)
+		}
+
+		runs, err := store.LoadArchivedRuns(h.kubeContext.Cluster, msg.CronJob.Namespace, msg.CronJob.Name)
+		if err != nil {
+			return nil, fmt.Errorf("load archived runs: %w", err)
+		}
+
+		// live jobs that were archived already are read from their pods
+		archived := []k8s.Job{}
+		for _, run := range runs {
+			if !slices.ContainsFunc(jobs, func(j k8s.Job) bool { return j.Name == run.Job.Name }) {
+				archived = append(archived, run.Job)
+			}
+		}
+
+		return tui.WrapJobs(jobs, archived), nil
+	case tui.CronJobContainersViewMsg:
+		containers, err := h.jobContainers(ctx, tui.Job{Job: msg.Job, Archived: msg.Archived})
+		if err != nil {
+			return nil, err
+		}
+
+		return tui.WrapContainers(containers, false), nil
+	case tui.JobDiffViewMsg:
+		left, err := h.jobLogs(ctx, msg.Left)
+		if err != nil {
+			return nil, fmt.Errorf("get logs of %s: %w", msg.Left.Name, err)
+		}
+
+		right, err := h.jobLogs(ctx, msg.Right)
+		if err != nil {
+			return nil, fmt.Errorf("get logs of %s: %w", msg.Right.Name, err)
+		}
+
+		return tui.WrapDiff(diff.Compare(left, right)), nil
+	case tui.PermissionsViewMsg:
+		checks, err := k8s.CheckPermissions(ctx, h.clientset, namespacePermissions(msg.Namespace))
+		if err != nil {
+			return nil, fmt.Errorf("check permissions: %w", err)
+		}
+
+		return tui.WrapPermissionChecks(checks), nil
+	case tui.BookmarksViewMsg:
+		cluster, namespace := h.kubeContext.Cluster, msg.Namespace
+		if msg.Local {
+			cluster, namespace = local.Cluster, local.Cluster
+		}
+
+		bookmarks, err := store.LoadBookmarks(cluster, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("load bookmarks: %w", err)
+		}
+
+		return tui.WrapBookmarks(bookmarks), nil
+	case tui.SessionViewMsg:
+		if h.replay == nil {
+			return nil, errors.New("no session is being replayed")
+		}
+
+		return tui.WrapStreams(h.replay.Streams), nil
+	case tui.FilesViewMsg:
+		sources, err := local.FindSources(h.files)
+		if err != nil {
+			return nil, fmt.Errorf("find files: %w", err)
+		}
+
+		return tui.WrapFiles(sources), nil
+	default:
+		return nil, fmt.Errorf("unknown message type %T", msg)
+	}
+}
+
+// jobContainers lists the containers of a job's pods, or of its archived run
+func (h handler) jobContainers(ctx context.Context, job tui.Job) ([]k8s.Container, error) {
+	if job.Archived {
+		run, found, err := store.LoadArchivedRun(h.kubeContext.Cluster, job.Namespace, job.Name)
+		if err != nil {
+			return nil, fmt.Errorf("load archived run: %w", err)
+		}
+		if !found {
+			return nil, fmt.Errorf("job %s is no longer archived", job.Name)
+		}
+
+		return run.Containers, nil
+	}
+
+	labelSelector := fmt.Sprintf("job-name=%s", job.Name)
+
+	containers, err := k8s.GetContainers(ctx, h.clientset, job.Namespace, labelSelector, "")
+	if err != nil {
+		return nil, fmt.Errorf("get job containers: %w", err)
+	}
+
+	return containers, nil
+}
+
+// jobLogs reads every line of every container of a job, to compare it with
+// another run. the names of the job and its pods are normalized away, since
+// they always differ between runs.
+func (h handler) jobLogs(ctx context.Context, job tui.Job) (diff.Side, error) {
+	containers, err := h.jobContainers(ctx, job)
+	if err != nil {
+		return diff.Side{}, err
+	}
+
+	// runs are lined up container by container
+	slices.SortFunc(containers, func(a, b k8s.Container) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Pod, b.Pod))
+	})
+
+	lines := []string{}
+	names := []string{}
+
+	for _, c := range containers {
+		var source pkg.LogSource = k8s.NewPodLogSource(
+			h.clientset,
+			h.kubeContext.Cluster,
+			c,
+			job.StartTime,
+			k8s.LogOptions{Timestamps: true},
+		)
+		if job.Archived {
+			source = archive.NewSource(h.kubeContext.Cluster, c, job.StartTime, time.Time{})
+		}
+
+		logs, err := readLogs(ctx, source, time.Time{})
+		if err != nil {
+			return diff.Side{}, fmt.Errorf("get logs of %s/%s: %w", c.Pod, c.Name, err)
+		}
+
+		lines = append(lines, logs...)
+		names = append(names, c.Pod, "<pod>")
+	}
+
+	names = append(names, job.Name, "<job>")
+
+	return diff.Side{Lines: lines, Normalizer: diff.NewNormalizer(names...)}, nil
+}
+
+func (h handler) hidden(namespace string) bool {
+	return slices.Contains(h.cfg.HiddenNamespaces, namespace)
+}
+
+// fallbackNamespaces offers namespaces we know of without listing them, for
+// users who can't list namespaces cluster-wide
+func (h handler) fallbackNamespaces() []list.Item {
+	items := []list.Item{}
+	seen := map[string]bool{}
+
+	add := func(source string, namespaces ...string) {
+		for _, n := range namespaces {
+			if n == "" || seen[n] {
+				continue
+			}
+			seen[n] = true
+			items = append(items, tui.Namespace{Name: n, Source: source})
+		}
+	}
+
+	add("kubeconfig context", h.kubeContext.Namespace)
+	add("config", h.cfg.Namespace)
+	add("config", h.cfg.Namespaces...)
+
+	history, err := store.NamespaceHistory.Load(h.kubeContext.Name)
+	if err != nil {
+		log.Printf("load namespace history: %v\n", err)
+	}
+
+	add("entered before", history...)
+
+	return append(items, tui.NamespacePrompt{})
+}
+
+// requiredPermissions lists what the user needs to load msg's view
+func requiredPermissions(msg tea.Msg) []k8s.Permission {
+	switch msg := msg.(type) {
+	case tui.NamespacesViewMsg:
+		return []k8s.Permission{
+			{Verb: "list", Resource: "namespaces"},
+		}
+	case tui.ContainersViewMsg:
+		return []k8s.Permission{
+			{Verb: "list", Resource: "pods", Namespace: msg.Namespace},
+		}
+	case tui.CronJobsViewMsg:
+		return []k8s.Permission{
+			{Verb: "list", Group: "batch", Resource: "cronjobs", Namespace: msg.Namespace},
+		}
+	case tui.CronJobJobsViewMsg:
+		return []k8s.Permission{
+			{Verb: "list", Group: "batch", Resource: "jobs", Namespace: msg.CronJob.Namespace},
+		}
+	case tui.CronJobContainersViewMsg:
+		if msg.Archived {
+			return nil
+		}
+		return []k8s.Permission{
+			{Verb: "list", Resource: "pods", Namespace: msg.Job.Namespace},
+		}
+	case tui.JobDiffViewMsg:
+		return []k8s.Permission{
+			{Verb: "list", Resource: "pods", Namespace: msg.CronJob.Namespace},
+			{Verb: "get", Resource: "pods", Subresource: "log", Namespace: msg.CronJob.Namespace},
+		}
+	default:
+		return nil
+	}
+}
+
+// namespacePermissions is everything the viewer may need in a namespace
+func namespacePermissions(namespace string) []k8s.Permission {
+	return []k8s.Permission{
+		{Verb: "list", Resource: "namespaces"},
+		{Verb: "list", Resource: "pods", Namespace: namespace},
+		{Verb: "get", Resource: "pods", Subresource: "log", Namespace: namespace},
+		{Verb: "list", Group: "batch", Resource: "cronjobs", Namespace: namespace},
+		{Verb: "get", Group: "batch", Resource: "cronjobs", Namespace: namespace},
+		{Verb: "list", Group: "batch", Resource: "jobs", Namespace: namespace},
+	}
+}
@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Permission struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	// Namespace is empty for cluster-scoped resources and all namespaces
+	Namespace string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	if p.Group != "" {
+		resource += "." + p.Group
+	}
+
+	scope := "cluster-wide"
+
+	if p.Namespace != "" {
+		scope = "in namespace " + p.Namespace
+	}
+
+	return fmt.Sprintf("%s %s %s", p.Verb, resource, scope)
+}
+
+type PermissionCheck struct {
+	Permission
+	Allowed bool
+	Reason  string
+}
+
+func CheckPermissions(
+	ctx context.Context,
+	clientset *kubernetes.Clientset,
+	permissions []Permission,
+) (
+	[]PermissionCheck,
+	error,
+) {
+	checks := []PermissionCheck{}
+
+	for _, p := range permissions {
+		review, err := clientset.
+			AuthorizationV1().
+			SelfSubjectAccessReviews().
+			Create(
+				ctx,
+				&authv1.SelfSubjectAccessReview{
+					Spec: authv1.SelfSubjectAccessReviewSpec{
+						ResourceAttributes: &authv1.ResourceAttributes{
+							Namespace:   p.Namespace,
+							Verb:        p.Verb,
+							Group:       p.Group,
+							Resource:    p.Resource,
+							Subresource: p.Subresource,
+						},
+					},
+				},
+				metav1.CreateOptions{},
+			)
+		if err != nil {
+			return nil, fmt.Errorf("review %s: %w", p, err)
+		}
+
+		checks = append(checks, PermissionCheck{
+			Permission: p,
+			Allowed:    review.Status.Allowed,
+			Reason:     review.Status.Reason,
+		})
+	}
+
+	return checks, nil
+}
+
+// MissingPermissionsError explains a Forbidden error with the permissions
+// the request needed but the user doesn't have.
+type MissingPermissionsError struct {
+	Err     error
+	Missing []Permission
+}
+
+func (e MissingPermissionsError) Error() string {
+	missing := make([]string, len(e.Missing))
+	for i, p := range e.Missing {
+		missing[i] = p.String()
+	}
+
+	return fmt.Sprintf(
+		"missing permission to %s: %v",
+		strings.Join(missing, ", "),
+		e.Err,
+	)
+}
+
+func (e MissingPermissionsError) Unwrap() error {
+	return e.Err
+}
+
+// DiagnoseForbidden replaces a Forbidden err with a MissingPermissionsError
+// listing which of the required permissions are missing. Any other err, or
+// one that's diagnosed already, is returned as is.
+func DiagnoseForbidden(
+	ctx context.Context,
+	clientset *kubernetes.Clientset,
+	err error,
+	required []Permission,
+) error {
+	if Classify(err) != ForbiddenError || len(required) == 0 {
+		return err
+	}
+	if errors.As(err, &MissingPermissionsError{}) {
+		return err
+	}
+
+	checks, checkErr := CheckPermissions(ctx, clientset, required)
+	if checkErr != nil {
+		return fmt.Errorf("%w (diagnose permissions: %v)", err, checkErr)
+	}
+
+	missing := []Permission{}
+
+	for _, c := range checks {
+		if !c.Allowed {
+			missing = append(missing, c.Permission)
+		}
+	}
+
+	if len(missing) == 0 {
+		return err
+	}
+
+	return MissingPermissionsError{Err: err, Missing: missing}
+}
@@ -1,15 +1,63 @@
 package k8s
 
 import (
+	"fmt"
+
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-func NewClientset(kubeconfig string) (*kubernetes.Clientset, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+func newClientConfig(kubeconfig string, context string) clientcmd.ClientConfig {
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: context},
+	)
+}
+
+// NewClientset builds a clientset from kubeconfig. An empty context uses the
+// kubeconfig's current context.
+func NewClientset(kubeconfig string, context string) (*kubernetes.Clientset, error) {
+	config, err := newClientConfig(kubeconfig, context).ClientConfig()
 	if err != nil {
 		return nil, err
 	}
 
 	return kubernetes.NewForConfig(config)
 }
+
+type KubeContext struct {
+	Name    string
+	Cluster string
+	// Namespace is the context's default namespace
+	Namespace string
+}
+
+func LoadKubeContext(kubeconfig string, context string) (KubeContext, error) {
+	cc := newClientConfig(kubeconfig, context)
+
+	raw, err := cc.RawConfig()
+	if err != nil {
+		return KubeContext{}, fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	name := context
+	if name == "" {
+		name = raw.CurrentContext
+	}
+
+	kc, ok := raw.Contexts[name]
+	if !ok {
+		return KubeContext{}, fmt.Errorf("context %q not found in kubeconfig", name)
+	}
+
+	namespace, _, err := cc.Namespace()
+	if err != nil {
+		return KubeContext{}, fmt.Errorf("get context namespace: %w", err)
+	}
+
+	return KubeContext{
+		Name:      name,
+		Cluster:   kc.Cluster,
+		Namespace: namespace,
+	}, nil
+}
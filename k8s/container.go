@@ -8,9 +8,9 @@ import (
 )
 
 type Container struct {
-	Namespace string
-	Pod       string
-	Name      string
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Name      string `json:"name"`
 }
 
 func GetContainers(
@@ -18,11 +18,12 @@ func GetContainers(
 	clientset *kubernetes.Clientset,
 	namespace string,
 	labelSelector string,
+	fieldSelector string,
 ) (
 	[]Container,
 	error,
 ) {
-	pods, err := GetPods(ctx, clientset, namespace, labelSelector)
+	pods, err := GetPods(ctx, clientset, namespace, labelSelector, fieldSelector)
 	if err != nil {
 		return nil, fmt.Errorf("load model data: %w", err)
 	}
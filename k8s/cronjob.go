@@ -5,16 +5,32 @@ import (
 	"fmt"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
 type CronJob struct {
-	Namespace        string
-	UID              types.UID
-	Name             string
-	LastScheduleTime time.Time
+	Namespace        string    `json:"namespace"`
+	UID              types.UID `json:"uid"`
+	Name             string    `json:"name"`
+	LastScheduleTime time.Time `json:"lastScheduleTime"`
+}
+
+func newCronJob(item batchv1.CronJob) CronJob {
+	lst := time.Time{}
+
+	if item.Status.LastScheduleTime != nil {
+		lst = item.Status.LastScheduleTime.Time
+	}
+
+	return CronJob{
+		Namespace:        item.Namespace,
+		UID:              item.UID,
+		Name:             item.Name,
+		LastScheduleTime: lst,
+	}
 }
 
 func GetCronJobs(
@@ -29,19 +45,33 @@ func GetCronJobs(
 		CronJobs(namespace).
 		List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("list pods: %w", err)
+		return nil, fmt.Errorf("list cron jobs: %w", err)
 	}
 
 	cronJobs := []CronJob{}
 
 	for _, item := range list.Items {
-		cronJobs = append(cronJobs, CronJob{
-			Namespace:        item.Namespace,
-			UID:              item.UID,
-			Name:             item.Name,
-			LastScheduleTime: item.Status.LastScheduleTime.Time,
-		})
+		cronJobs = append(cronJobs, newCronJob(item))
 	}
 
 	return cronJobs, nil
 }
+
+func GetCronJob(
+	ctx context.Context,
+	clientset *kubernetes.Clientset,
+	namespace string,
+	name string,
+) (
+	CronJob,
+	error,
+) {
+	item, err := clientset.BatchV1().
+		CronJobs(namespace).
+		Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return CronJob{}, fmt.Errorf("get cron job: %w", err)
+	}
+
+	return newCronJob(*item), nil
+}
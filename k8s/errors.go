@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type ErrorKind string
+
+const (
+	UnknownError      ErrorKind = "error"
+	UnauthorizedError ErrorKind = "unauthorized"
+	ForbiddenError    ErrorKind = "forbidden"
+	NotFoundError     ErrorKind = "not found"
+	TimeoutError      ErrorKind = "timeout"
+	UnreachableError  ErrorKind = "unreachable"
+)
+
+func Classify(err error) ErrorKind {
+	switch {
+	case err == nil:
+		return ""
+	case apierrors.IsUnauthorized(err):
+		return UnauthorizedError
+	case apierrors.IsForbidden(err):
+		return ForbiddenError
+	case apierrors.IsNotFound(err):
+		return NotFoundError
+	case errors.Is(err, context.DeadlineExceeded),
+		apierrors.IsTimeout(err),
+		apierrors.IsServerTimeout(err):
+		return TimeoutError
+	}
+
+	if ne := net.Error(nil); errors.As(err, &ne) && ne.Timeout() {
+		return TimeoutError
+	}
+
+	if de := (*net.DNSError)(nil); errors.As(err, &de) {
+		return UnreachableError
+	}
+
+	if oe := (*net.OpError)(nil); errors.As(err, &oe) && oe.Op == "dial" {
+		return UnreachableError
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH) {
+		return UnreachableError
+	}
+
+	return UnknownError
+}
+
+// IsTransient reports whether err is likely to go away on its own, so the
+// request is worth retrying.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if Classify(err) == TimeoutError ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
@@ -6,18 +6,30 @@ import (
 	"slices"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
 type Job struct {
-	Namespace      string
-	Name           string
-	StartTime      time.Time
-	CompletionTime time.Time
-	Failed         int32
-	Succeeded      int32
+	Namespace      string    `json:"namespace"`
+	Name           string    `json:"name"`
+	StartTime      time.Time `json:"startTime"`
+	CompletionTime time.Time `json:"completionTime"`
+	Failed         int32     `json:"failed"`
+	Succeeded      int32     `json:"succeeded"`
+	// Finished is set once the job completed or failed for good, so its
+	// pods won't log anything more
+	Finished bool `json:"finished"`
+}
+
+func finished(item batchv1.Job) bool {
+	return slices.ContainsFunc(item.Status.Conditions, func(c batchv1.JobCondition) bool {
+		return (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) &&
+			c.Status == v1.ConditionTrue
+	})
 }
 
 func GetJobs(
@@ -61,6 +73,7 @@ func GetJobs(
 				CompletionTime: ct,
 				Failed:         item.Status.Failed,
 				Succeeded:      item.Status.Succeeded,
+				Finished:       finished(item),
 			})
 		}
 	}
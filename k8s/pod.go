@@ -13,6 +13,7 @@ func GetPods(
 	clientset *kubernetes.Clientset,
 	namespace string,
 	labelSelector string,
+	fieldSelector string,
 ) (
 	[]v1.Pod,
 	error,
@@ -20,7 +21,10 @@ func GetPods(
 	list, err := clientset.
 		CoreV1().
 		Pods(namespace).
-		List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		List(ctx, metav1.ListOptions{
+			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
+		})
 	if err != nil {
 		return nil, err
 	}
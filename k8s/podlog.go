@@ -5,18 +5,62 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/joshuasprow/log-viewer/pkg"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+type LogOptions struct {
+	// TailLines limits the number of lines returned; zero or less returns all
+	TailLines int64
+	// Since only returns lines newer than a relative duration
+	Since time.Duration
+	// SinceTime only returns lines logged at or after it. it replaces Since
+	// and TailLines, since it asks for everything from that point on.
+	SinceTime time.Time
+	// Timestamps prefixes every line with its RFC3339 timestamp
+	Timestamps bool
+}
+
+func (o LogOptions) podLogOptions(container string, follow bool) *v1.PodLogOptions {
+	opts := &v1.PodLogOptions{
+		Container:  container,
+		Follow:     follow,
+		Timestamps: o.Timestamps,
+	}
+
+	if !o.SinceTime.IsZero() {
+		opts.SinceTime = pkg.Ptr(metav1.NewTime(o.SinceTime))
+		return opts
+	}
+
+	if o.TailLines > 0 {
+		opts.TailLines = pkg.Ptr(o.TailLines)
+	}
+	if o.Since > 0 {
+		opts.SinceTime = pkg.Ptr(metav1.NewTime(time.Now().Add(-o.Since)))
+	}
+
+	return opts
+}
+
+// logPermissions is what reading the logs of a namespace's pods needs
+func logPermissions(namespace string) []Permission {
+	return []Permission{
+		{Verb: "get", Resource: "pods", Subresource: "log", Namespace: namespace},
+	}
+}
+
 func GetPodLogs(
 	ctx context.Context,
 	clientset *kubernetes.Clientset,
 	namespace string,
 	pod string,
 	container string,
+	opts LogOptions,
 ) (
 	[]string,
 	error,
@@ -24,78 +68,79 @@ func GetPodLogs(
 	data, err := clientset.
 		CoreV1().
 		Pods(namespace).
-		GetLogs(
-			pod,
-			&v1.PodLogOptions{
-				Container: container,
-				TailLines: pkg.Ptr(int64(10)),
-			},
-		).
+		GetLogs(pod, opts.podLogOptions(container, false)).
 		Do(ctx).
 		Raw()
 	if err != nil {
-		return nil, err
+		return nil, DiagnoseForbidden(ctx, clientset, err, logPermissions(namespace))
 	}
 
-	logs := []string{}
+	// lines aren't trimmed, since the indentation of stack traces is what
+	// groups them
+	logs := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
 
-	for _, line := range strings.Split(string(data), "\n") {
-		l := strings.TrimSpace(line)
-
-		if l == "" {
-			continue
-		}
+	for i, line := range logs {
+		logs[i] = strings.TrimSuffix(line, "\r")
+	}
 
-		logs = append(logs, l)
+	if len(logs) == 1 && logs[0] == "" {
+		return []string{}, nil
 	}
 
 	return logs, nil
 }
 
-// todo: stream logs when view is initialized
 func StreamPodLogs(
 	ctx context.Context,
 	clientset *kubernetes.Clientset,
 	namespace string,
 	pod string,
 	container string,
+	opts LogOptions,
 	logsCh chan<- pkg.Result[string],
 ) {
 	defer close(logsCh)
 
 	type R = pkg.Result[string]
 
+	// send gives up once ctx is done, so a reader that went away can't
+	// leave the stream blocked
+	send := func(r R) bool {
+		select {
+		case logsCh <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
 	req := clientset.
 		CoreV1().
 		Pods(namespace).
-		GetLogs(
-			pod,
-			&v1.PodLogOptions{
-				Container: container,
-				Follow:    true,
-				TailLines: pkg.Ptr(int64(10)),
-			},
-		)
+		GetLogs(pod, opts.podLogOptions(container, true))
 
 	stream, err := req.Stream(ctx)
 	if err != nil {
-		logsCh <- R{Err: fmt.Errorf("get stream: %w", err)}
+		err = DiagnoseForbidden(ctx, clientset, err, logPermissions(namespace))
+		send(R{Err: fmt.Errorf("get stream: %w", err)})
 		return
 	}
 	defer func() {
 		if err := stream.Close(); err != nil {
-			logsCh <- R{Err: fmt.Errorf("close stream: %w", err)}
+			send(R{Err: fmt.Errorf("close stream: %w", err)})
 		}
 	}()
 
 	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	for scanner.Scan() {
-		if err := scanner.Err(); err != nil {
-			logsCh <- R{Err: fmt.Errorf("scan error: %w", err)}
+		if !send(R{V: scanner.Text()}) {
 			return
 		}
+	}
 
-		logsCh <- R{V: scanner.Text()}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		send(R{Err: fmt.Errorf("scan error: %w", err)})
 	}
 }
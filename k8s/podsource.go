@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+	"k8s.io/client-go/kubernetes"
+)
+
+// streamBuffer lets a stream read ahead while its reader is busy
+const streamBuffer = 256
+
+// PodLogSource reads the logs of a container from the pod log API
+type PodLogSource struct {
+	clientset *kubernetes.Clientset
+	meta      pkg.LogMeta
+	opts      LogOptions
+}
+
+func NewPodLogSource(
+	clientset *kubernetes.Clientset,
+	cluster string,
+	container Container,
+	start time.Time,
+	opts LogOptions,
+) PodLogSource {
+	return PodLogSource{
+		clientset: clientset,
+		meta: pkg.LogMeta{
+			Cluster:   cluster,
+			Namespace: container.Namespace,
+			Pod:       container.Pod,
+			Container: container.Name,
+			Start:     start,
+		},
+		opts: opts,
+	}
+}
+
+func (s PodLogSource) Meta() pkg.LogMeta {
+	return s.meta
+}
+
+func (s PodLogSource) Open(ctx context.Context) ([]string, error) {
+	return GetPodLogs(ctx, s.clientset, s.meta.Namespace, s.meta.Pod, s.meta.Container, s.opts)
+}
+
+func (s PodLogSource) Seek(ctx context.Context, t time.Time) ([]string, error) {
+	opts := s.opts
+	opts.SinceTime = t
+
+	return GetPodLogs(ctx, s.clientset, s.meta.Namespace, s.meta.Pod, s.meta.Container, opts)
+}
+
+func (s PodLogSource) Follow(ctx context.Context) <-chan pkg.Result[string] {
+	logsCh := make(chan pkg.Result[string], streamBuffer)
+
+	go StreamPodLogs(
+		ctx,
+		s.clientset,
+		s.meta.Namespace,
+		s.meta.Pod,
+		s.meta.Container,
+		s.opts,
+		logsCh,
+	)
+
+	return logsCh
+}
+
+func (s PodLogSource) Close() error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package local
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// streamBuffer lets a stream read ahead while its reader is busy
+const streamBuffer = 256
+
+// FileSource reads a local log file and the files it was rotated to
+type FileSource struct {
+	path string
+}
+
+func NewFileSource(path string) FileSource {
+	return FileSource{path: path}
+}
+
+// Meta names the file's directory as its pod, so it's bookmarked like a
+// container named after the file
+func (s FileSource) Meta() pkg.LogMeta {
+	dir, name := filepath.Split(s.path)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	return pkg.LogMeta{
+		Cluster:   Cluster,
+		Namespace: Cluster,
+		Pod:       dir,
+		Container: name,
+	}
+}
+
+// Open reads every line of the file, rotated ones first
+func (s FileSource) Open(ctx context.Context) ([]string, error) {
+	lines := []string{}
+
+	for r := range s.read(ctx, false) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		lines = append(lines, r.V)
+	}
+
+	return lines, ctx.Err()
+}
+
+// Seek reads every line, since lines of files aren't known to have times
+func (s FileSource) Seek(ctx context.Context, t time.Time) ([]string, error) {
+	return s.Open(ctx)
+}
+
+// Follow reads every line, then follows the file like tail -F
+func (s FileSource) Follow(ctx context.Context) <-chan pkg.Result[string] {
+	return s.read(ctx, true)
+}
+
+func (s FileSource) Close() error {
+	return nil
+}
+
+func (s FileSource) read(ctx context.Context, follow bool) <-chan pkg.Result[string] {
+	logsCh := make(chan pkg.Result[string], streamBuffer)
+
+	// the rotated files are found when reading starts, since they change
+	src, err := FindSource(s.path)
+	if err != nil {
+		logsCh <- pkg.Result[string]{Err: err}
+		close(logsCh)
+		return logsCh
+	}
+
+	go tail(ctx, src, follow, logsCh)
+
+	return logsCh
+}
@@ -0,0 +1,225 @@
+package local
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// pollInterval is how often a followed file is checked for new lines
+const pollInterval = 250 * time.Millisecond
+
+// tail sends the lines of the rotated files, oldest first, then of the
+// file itself. with follow set the file is then followed like tail -F:
+// through truncation and on to the new file once it's rotated. gzipped
+// files end after their lines.
+func tail(
+	ctx context.Context,
+	src Source,
+	follow bool,
+	logsCh chan<- pkg.Result[string],
+) {
+	defer close(logsCh)
+
+	type R = pkg.Result[string]
+
+	send := func(r R) bool {
+		select {
+		case logsCh <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	sendLine := func(line string) bool {
+		return send(R{V: line})
+	}
+
+	for _, path := range src.Rotated {
+		if err := readFile(path, sendLine); err != nil {
+			if !send(R{Err: err}) {
+				return
+			}
+		}
+	}
+
+	if !follow || src.Compressed() {
+		if err := readFile(src.Path, sendLine); err != nil {
+			send(R{Err: err})
+		}
+		return
+	}
+
+	t := &tailer{path: src.Path}
+
+	if err := t.open(); err != nil {
+		send(R{Err: err})
+		return
+	}
+	defer t.close()
+
+	for {
+		// t.f is nil while a rotated file's successor is missing
+		if t.f != nil {
+			if err := t.read(sendLine); err != nil {
+				send(R{Err: err})
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+
+		if t.f == nil {
+			t.open()
+			continue
+		}
+
+		info, err := os.Stat(t.path)
+
+		switch {
+		case err != nil:
+			// rotated away, and the new file isn't there yet
+		case !os.SameFile(t.info, info):
+			// finish the rotated file before moving on to the new one
+			if err := t.read(sendLine); err != nil {
+				send(R{Err: err})
+				return
+			}
+			t.flush(sendLine)
+			t.close()
+			t.open()
+		case info.Size() < t.offset:
+			if err := t.truncated(); err != nil {
+				send(R{Err: err})
+				return
+			}
+		}
+	}
+}
+
+// readFile sends every line of the file at path, which may be gzipped
+func readFile(path string, send func(string) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+
+	// gzipped files are told by their magic number, not their name
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("open gzip %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if !send(strings.TrimSuffix(scanner.Text(), "\r")) {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// tailer reads the lines appended to a file
+type tailer struct {
+	path   string
+	f      *os.File
+	info   os.FileInfo
+	r      *bufio.Reader
+	offset int64
+	// partial is a line that's still being written
+	partial string
+}
+
+func (t *tailer) open() error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", t.path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat %s: %w", t.path, err)
+	}
+
+	t.f, t.info, t.r, t.offset, t.partial = f, info, bufio.NewReader(f), 0, ""
+
+	return nil
+}
+
+func (t *tailer) close() {
+	if t.f != nil {
+		t.f.Close()
+		t.f = nil
+	}
+}
+
+// truncated starts over at the start of a file that was truncated
+func (t *tailer) truncated() error {
+	if _, err := t.f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seek %s: %w", t.path, err)
+	}
+
+	t.r.Reset(t.f)
+	t.offset, t.partial = 0, ""
+
+	return nil
+}
+
+// read sends the complete lines written since the last read
+func (t *tailer) read(send func(string) bool) error {
+	for {
+		line, err := t.r.ReadString('\n')
+		t.offset += int64(len(line))
+
+		if err == io.EOF {
+			t.partial += line
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", t.path, err)
+		}
+
+		line = t.partial + strings.TrimRight(line, "\r\n")
+		t.partial = ""
+
+		if !send(line) {
+			return nil
+		}
+	}
+}
+
+// flush sends the last line of a file that won't be written to anymore
+func (t *tailer) flush(send func(string) bool) {
+	if t.partial != "" {
+		send(t.partial)
+		t.partial = ""
+	}
+}
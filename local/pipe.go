@@ -0,0 +1,135 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// StdinPath is the path that stands for the lines piped on stdin
+const StdinPath = "-"
+
+// Pipe keeps the lines of a stream that can only be read once, e.g. stdin,
+// so every view of it gets all of them
+type Pipe struct {
+	mu    sync.Mutex
+	lines []string
+	err   error
+	done  bool
+	// added is closed, then replaced, whenever lines are added
+	added chan struct{}
+}
+
+// NewPipe reads the lines of r until it ends
+func NewPipe(r io.Reader) *Pipe {
+	p := &Pipe{added: make(chan struct{})}
+	go p.read(r)
+	return p
+}
+
+func (p *Pipe) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		p.add(func() {
+			p.lines = append(p.lines, strings.TrimSuffix(scanner.Text(), "\r"))
+		})
+	}
+
+	p.add(func() {
+		if err := scanner.Err(); err != nil {
+			p.err = fmt.Errorf("read stdin: %w", err)
+		}
+		p.done = true
+	})
+}
+
+func (p *Pipe) add(update func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	update()
+
+	close(p.added)
+	p.added = make(chan struct{})
+}
+
+func (p *Pipe) Meta() pkg.LogMeta {
+	return pkg.LogMeta{
+		Cluster:   Cluster,
+		Namespace: Cluster,
+		Container: "stdin",
+	}
+}
+
+// Open returns the lines read so far
+func (p *Pipe) Open(ctx context.Context) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.lines), p.err
+}
+
+// Seek returns the lines read so far, since piped lines have no times to
+// seek by
+func (p *Pipe) Seek(ctx context.Context, t time.Time) ([]string, error) {
+	return p.Open(ctx)
+}
+
+// Follow sends the lines read so far, then the rest as they're read
+func (p *Pipe) Follow(ctx context.Context) <-chan pkg.Result[string] {
+	logsCh := make(chan pkg.Result[string], streamBuffer)
+	go p.follow(ctx, logsCh)
+	return logsCh
+}
+
+// Close leaves the lines to other views of the pipe
+func (p *Pipe) Close() error {
+	return nil
+}
+
+func (p *Pipe) follow(ctx context.Context, logsCh chan<- pkg.Result[string]) {
+	defer close(logsCh)
+
+	sent := 0
+
+	for {
+		p.mu.Lock()
+		lines := p.lines[sent:]
+		err, done, added := p.err, p.done, p.added
+		p.mu.Unlock()
+
+		for _, line := range lines {
+			select {
+			case logsCh <- pkg.Result[string]{V: line}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		sent += len(lines)
+
+		if done {
+			if err != nil {
+				select {
+				case logsCh <- pkg.Result[string]{Err: err}:
+				case <-ctx.Done():
+				}
+			}
+			return
+		}
+
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
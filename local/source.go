@@ -0,0 +1,176 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Cluster is the cluster name local files are bookmarked under
+const Cluster = "local"
+
+// rotatedSuffix matches what logrotate and friends add to the name of a
+// rotated file, e.g. "app.log.1", "app.log.2.gz" or "app.log-20240101.gz"
+var rotatedSuffix = regexp.MustCompile(`^[.-]\d+(\.gz)?$|^\.gz$`)
+
+// Source is a log file and the files it was rotated to, oldest first
+type Source struct {
+	Path    string
+	Rotated []string
+	Size    int64
+	ModTime time.Time
+}
+
+// Compressed reports whether the file is gzipped, so can't be followed
+func (s Source) Compressed() bool {
+	return strings.HasSuffix(s.Path, ".gz")
+}
+
+// FindSources lists the log files of paths. directories are expanded to
+// the files in them, and rotated files are grouped with the file they were
+// rotated from.
+func FindSources(paths []string) ([]Source, error) {
+	sources := []Source{}
+	seen := map[string]bool{}
+
+	add := func(s Source) {
+		if !seen[s.Path] {
+			seen[s.Path] = true
+			sources = append(sources, s)
+		}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			s, err := FindSource(path)
+			if err != nil {
+				return nil, err
+			}
+			add(s)
+			continue
+		}
+
+		dirSources, err := findDirSources(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range dirSources {
+			add(s)
+		}
+	}
+
+	return sources, nil
+}
+
+// FindSource finds the rotated files of the file at path
+func FindSource(path string) (Source, error) {
+	path = filepath.Clean(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Source{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	files, err := listFiles(filepath.Dir(path))
+	if err != nil {
+		return Source{}, err
+	}
+
+	return newSource(path, info, files), nil
+}
+
+func findDirSources(dir string) ([]Source, error) {
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []Source{}
+
+	for path, info := range files {
+		// rotated files are listed with the file they came from
+		if base, ok := rotatedFrom(path); ok {
+			if _, ok := files[base]; ok {
+				continue
+			}
+		}
+
+		sources = append(sources, newSource(path, info, files))
+	}
+
+	slices.SortFunc(sources, func(a, b Source) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return sources, nil
+}
+
+func newSource(path string, info os.FileInfo, files map[string]os.FileInfo) Source {
+	s := Source{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+
+	for p := range files {
+		if base, ok := rotatedFrom(p); ok && base == path {
+			s.Rotated = append(s.Rotated, p)
+		}
+	}
+
+	slices.SortFunc(s.Rotated, func(a, b string) int {
+		return files[a].ModTime().Compare(files[b].ModTime())
+	})
+
+	return s
+}
+
+// rotatedFrom returns the path of the file path was rotated from
+func rotatedFrom(path string) (string, bool) {
+	dir, name := filepath.Split(path)
+
+	for i := 1; i < len(name); i++ {
+		if name[i] != '.' && name[i] != '-' {
+			continue
+		}
+		if rotatedSuffix.MatchString(name[i:]) {
+			return filepath.Join(dir, name[:i]), true
+		}
+	}
+
+	return "", false
+}
+
+// listFiles lists the regular, non-hidden files in dir by path
+func listFiles(dir string) (map[string]os.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir %s: %w", dir, err)
+	}
+
+	files := map[string]os.FileInfo{}
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		files[filepath.Join(dir, e.Name())] = info
+	}
+
+	return files, nil
+}
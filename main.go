@@ -2,27 +2,179 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/archive"
+	"github.com/joshuasprow/log-viewer/cli"
+	"github.com/joshuasprow/log-viewer/dispatch"
 	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/local"
 	"github.com/joshuasprow/log-viewer/models"
 	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/session"
 	"github.com/joshuasprow/log-viewer/tui"
 	"k8s.io/client-go/kubernetes"
 )
 
 func main() {
-	cfg, err := pkg.LoadConfig()
+	profile := flag.String("profile", "", "named profile from the config file")
+	record := flag.String("record", "", "record the streamed lines to a session file")
+	flag.Parse()
+
+	cfg, err := pkg.LoadConfig(*profile)
 	check("load config", err)
 
-	clientset, err := k8s.NewClientset(cfg.Kubeconfig)
+	// recorded sessions are replayed without a cluster
+	if args := flag.Args(); len(args) > 0 && args[0] == "replay" {
+		path, speed, err := parseReplayArgs(args[1:])
+		check("replay", err)
+
+		replay, err := session.Load(path)
+		check("load session", err)
+
+		h := handler{
+			cfg:         cfg,
+			kubeContext: k8s.KubeContext{Cluster: local.Cluster},
+			replay:      replay,
+			speed:       speed,
+		}
+
+		runTUI(cfg, h, func(ctx context.Context, dispatcher tui.Dispatcher) tea.Model {
+			return models.Local(ctx, cfg, dispatcher, h, tui.SessionViewMsg{})
+		})
+		return
+	}
+
+	// local files are viewed without loading a kubeconfig at all
+	if args := flag.Args(); len(args) > 0 && args[0] == "file" {
+		if len(args) < 2 {
+			check("file", errors.New("usage: log-viewer file <path...>"))
+		}
+
+		h := handler{
+			cfg:         cfg,
+			kubeContext: k8s.KubeContext{Cluster: local.Cluster},
+			files:       args[1:],
+			recorder:    openRecorder(*record),
+		}
+
+		runTUI(cfg, h, func(ctx context.Context, dispatcher tui.Dispatcher) tea.Model {
+			return models.Local(ctx, cfg, dispatcher, h, tui.FilesViewMsg{})
+		})
+		return
+	}
+
+	// logs piped on stdin are paged, with keys read from the terminal
+	if args := flag.Args(); len(args) == 1 && args[0] == local.StdinPath {
+		info, err := os.Stdin.Stat()
+		check("stat stdin", err)
+
+		if info.Mode()&os.ModeCharDevice != 0 {
+			check("read stdin", errors.New("nothing piped, e.g. kubectl logs <pod> | log-viewer -"))
+		}
+
+		h := handler{
+			cfg:         cfg,
+			kubeContext: k8s.KubeContext{Cluster: local.Cluster},
+			stdin:       local.NewPipe(os.Stdin),
+			recorder:    openRecorder(*record),
+		}
+
+		home := tui.FileLogsViewMsg{Path: local.StdinPath}
+
+		runTUI(cfg, h, func(ctx context.Context, dispatcher tui.Dispatcher) tea.Model {
+			return models.Local(ctx, cfg, dispatcher, h, home)
+		}, tea.WithInputTTY())
+		return
+	}
+
+	clientset, err := k8s.NewClientset(cfg.Kubeconfig, cfg.Context)
 	check("create k8s clientset", err)
 
-	ctx := context.Background()
-	msgCh := make(chan tea.Msg)
+	kubeContext, err := k8s.LoadKubeContext(cfg.Kubeconfig, cfg.Context)
+	check("load kube context", err)
+
+	cfg.Context = kubeContext.Name
+
+	if args := flag.Args(); len(args) > 0 && args[0] == "archive" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		err := runArchive(ctx, cfg, clientset, kubeContext.Cluster, args[1:])
+		stop()
+		check("archive", err)
+		return
+	}
+
+	if args := flag.Args(); len(args) > 0 && cli.IsCommand(args[0]) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		err := cli.Run(ctx, cfg, clientset, args, os.Stdout)
+		stop()
+		check("run command", err)
+		return
+	}
+
+	h := handler{
+		cfg:         cfg,
+		clientset:   clientset,
+		kubeContext: kubeContext,
+		logOptions:  k8s.LogOptions{TailLines: cfg.TailLines, Timestamps: true},
+		files:       []string{"."},
+		recorder:    openRecorder(*record),
+	}
+
+	if len(cfg.Archive.CronJobs) > 0 {
+		archiver := archive.New(clientset, kubeContext.Cluster, cfg.Archive, func(format string, args ...any) {
+			log.Printf(format+"\n", args...)
+		})
+		h.archiver = &archiver
+	}
+
+	runTUI(cfg, h, func(ctx context.Context, dispatcher tui.Dispatcher) tea.Model {
+		return models.Main(ctx, cfg, kubeContext, dispatcher, h)
+	})
+}
+
+// runTUI applies the config to the tui package and runs the program with
+// the model newModel returns
+func runTUI(
+	cfg pkg.Config,
+	h handler,
+	newModel func(ctx context.Context, dispatcher tui.Dispatcher) tea.Model,
+	opts ...tea.ProgramOption,
+) {
+	var err error
+
+	tui.Keys, err = tui.NewKeyMap(cfg.Keys.Preset, cfg.Keys.Bindings)
+	check("load key bindings", err)
+
+	tui.ActiveTheme, err = tui.LoadTheme(cfg.Theme)
+	check("load theme", err)
+
+	tui.TimestampFormat = cfg.TimestampFormat
+	tui.GapThreshold = cfg.GapThreshold
+
+	tui.Parsers = cfg.Parsers
+
+	tui.Multiline, err = pkg.NewMultiline(cfg.Multiline)
+	check("load multiline rules", err)
+
+	if cfg.TimeZone != "" {
+		tui.TimeZone, err = time.LoadLocation(cfg.TimeZone)
+		check("load time zone", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	logFile, err := tea.LogToFile("tmp/debug.log", "")
 	check("log to file", err)
@@ -30,105 +182,121 @@ func main() {
 
 	log.SetOutput(logFile)
 
+	dispatcher := dispatch.New(dispatch.DefaultWorkers, h.loadItems)
+
 	prg := tea.NewProgram(
-		models.Main(msgCh),
-		tea.WithAltScreen(),
-		tea.WithContext(ctx),
+		newModel(ctx, dispatcher),
+		append([]tea.ProgramOption{tea.WithAltScreen(), tea.WithContext(ctx)}, opts...)...,
 	)
 
-	go handleMessages(ctx, clientset, prg, msgCh)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx, prg.Send)
+	}()
+
+	if h.archiver != nil {
+		go h.archiver.Run(ctx)
+	}
 
 	_, err = prg.Run()
+
+	// cancels every view's context, then waits for in-flight requests and
+	// streams to shut down
+	cancel()
+	<-done
+
+	if h.recorder != nil {
+		check("record session", h.recorder.Close())
+	}
+
 	check("run program", err)
 }
 
-func check(msg string, err error) {
-	if err != nil {
-		fmt.Printf("%s: %v\n", msg, err)
-		os.Exit(1)
+// openRecorder starts recording a session to path, if it's set
+func openRecorder(path string) *session.Recorder {
+	if path == "" {
+		return nil
 	}
+
+	recorder, err := session.NewRecorder(path)
+	check("record session", err)
+
+	return recorder
 }
 
-func handleMessage(
-	ctx context.Context,
-	clientset *kubernetes.Clientset,
-	prg *tea.Program,
-	msg tea.Msg,
-) error {
-	// always bounce the message back to the main model
-	prg.Send(msg)
-
-	switch msg := msg.(type) {
-	case tui.NamespacesViewMsg:
-		namespaces, err := k8s.GetNamespaces(ctx, clientset)
-		if err != nil {
-			return fmt.Errorf("get namespaces: %w", err)
-		}
+// parseReplayArgs parses the arguments of the replay command: a session
+// file and the speed to play it at
+func parseReplayArgs(args []string) (string, float64, error) {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: log-viewer replay [--speed 1] <session>")
+		fs.PrintDefaults()
+	}
 
-		prg.Send(tui.WrapNamespaces(namespaces))
-	case tui.ApisViewMsg:
-		prg.Send(tui.GetApis())
-	case tui.ContainersViewMsg:
-		containers, err := k8s.GetContainers(ctx, clientset, msg.Namespace, "")
-		if err != nil {
-			return fmt.Errorf("get containers: %w", err)
-		}
+	speed := fs.Float64("speed", 1, "playback speed, e.g. 10 for ten times as fast; 0 plays back instantly")
 
-		prg.Send(tui.WrapContainers(containers))
-	case tui.ContainerLogsViewMsg:
-		logs, err := k8s.GetPodLogs(ctx, clientset, msg.Container.Namespace, msg.Container.Pod, msg.Container.Name)
-		if err != nil {
-			return fmt.Errorf("get pod logs: %w", err)
-		}
+	if err := fs.Parse(args); err != nil {
+		return "", 0, err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return "", 0, errors.New("expected a session file")
+	}
 
-		prg.Send(tui.WrapLogs(logs))
-	case tui.CronJobsViewMsg:
-		cronJobs, err := k8s.GetCronJobs(ctx, clientset, msg.Namespace)
-		if err != nil {
-			return fmt.Errorf("get cron jobs: %w", err)
-		}
+	return fs.Arg(0), *speed, nil
+}
 
-		prg.Send(tui.WrapCronJobs(cronJobs))
-	case tui.CronJobJobsViewMsg:
-		jobs, err := k8s.GetJobs(ctx, clientset, msg.CronJob.Namespace, msg.CronJob.UID)
-		if err != nil {
-			return fmt.Errorf("get jobs: %w", err)
-		}
+// runArchive archives the logs of the configured cron jobs, or the ones
+// given, until interrupted. --once sweeps a single time.
+func runArchive(
+	ctx context.Context,
+	cfg pkg.Config,
+	clientset *kubernetes.Clientset,
+	cluster string,
+	args []string,
+) error {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: log-viewer archive [--once] [<namespace>/<cronjob pattern>...]")
+		fs.PrintDefaults()
+	}
 
-		prg.Send(tui.WrapJobs(jobs))
-	case tui.CronJobContainersViewMsg:
-		labelSelector := fmt.Sprintf("job-name=%s", msg.Job.Name)
+	once := fs.Bool("once", false, "archive the finished jobs once, instead of every interval")
 
-		containers, err := k8s.GetContainers(ctx, clientset, msg.Job.Namespace, labelSelector)
-		if err != nil {
-			return fmt.Errorf("get job containers: %w", err)
-		}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
 
-		prg.Send(tui.WrapContainers(containers))
-	case tui.CronJobLogsViewMsg:
-		logs, err := k8s.GetPodLogs(ctx, clientset, msg.Container.Namespace, msg.Container.Pod, msg.Container.Name)
-		if err != nil {
-			return fmt.Errorf("get pod logs: %w", err)
+	if fs.NArg() > 0 {
+		for _, p := range fs.Args() {
+			if err := pkg.CheckCronJobPattern(p); err != nil {
+				return err
+			}
 		}
+		cfg.Archive.CronJobs = fs.Args()
+	}
+	if len(cfg.Archive.CronJobs) == 0 {
+		return errors.New("no cron jobs to archive, set archive.cronJobs in the config or pass some")
+	}
+
+	archiver := archive.New(clientset, cluster, cfg.Archive, func(format string, args ...any) {
+		fmt.Printf(format+"\n", args...)
+	})
 
-		prg.Send(tui.WrapLogs(logs))
-	default:
-		return fmt.Errorf("unknown message type %T", msg)
+	if *once {
+		return archiver.Sweep(ctx)
 	}
 
+	archiver.Run(ctx)
+
 	return nil
 }
 
-func handleMessages(
-	ctx context.Context,
-	clientset *kubernetes.Clientset,
-	prg *tea.Program,
-	msgCh <-chan tea.Msg,
-) {
-	for msg := range msgCh {
-		if err := handleMessage(ctx, clientset, prg, msg); err != nil {
-			log.Printf("handle message: %v\n", err)
-			prg.Send(err)
-		}
+func check(msg string, err error) {
+	if err != nil {
+		fmt.Printf("%s: %v\n", msg, err)
+		os.Exit(1)
 	}
 }
@@ -9,28 +9,40 @@ import (
 func Apis(
 	size tea.WindowSizeMsg,
 	namespace string,
-	msgCh chan<- tea.Msg,
 ) tea.Model {
 	options := defaults.ListModelOptions[tui.Api]{
-		Title: tui.RenderTitle(namespace, "select an API"),
-		OnEnter: func(selected tui.Api, msgCh chan<- tea.Msg) {
+		Title: tui.RenderTitle(tui.NamespaceTitle(namespace), "select an API"),
+		OnEnter: func(selected tui.Api) tea.Msg {
 			switch selected {
 			case tui.ContainersApi:
-				msgCh <- tui.ContainersViewMsg{
+				return tui.ContainersViewMsg{
 					Namespace: namespace,
 					Api:       selected,
 				}
 			case tui.CronJobsApi:
-				msgCh <- tui.CronJobsViewMsg{
+				return tui.CronJobsViewMsg{
 					Namespace: namespace,
 					Api:       selected,
 				}
+			case tui.PermissionsApi:
+				return tui.PermissionsViewMsg{
+					Namespace: namespace,
+				}
+			case tui.BookmarksApi:
+				return tui.BookmarksViewMsg{
+					Namespace: namespace,
+				}
+			case tui.FilesApi:
+				return tui.FilesViewMsg{
+					Namespace: namespace,
+				}
 			}
+			return nil
 		},
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.NamespacesViewMsg{}
+		OnEsc: func() tea.Msg {
+			return tui.NamespacesViewMsg{}
 		},
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	return defaults.NewListModel(size, options)
 }
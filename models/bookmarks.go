@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/local"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/store"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+type timelineExportedMsg struct {
+	path string
+	err  error
+}
+
+type bookmarksModel struct {
+	defaults.ListModel[tui.Bookmark]
+	title string
+}
+
+// Bookmarks lists the bookmarks of a namespace of cluster, or of local
+// files
+func Bookmarks(
+	size tea.WindowSizeMsg,
+	cluster string,
+	msg tui.BookmarksViewMsg,
+) tea.Model {
+	remove := tui.Keys.Bookmark
+	remove.SetHelp(remove.Help().Key, "remove bookmark")
+
+	namespace := msg.Namespace
+	title := tui.NamespaceTitle(namespace)
+
+	// local files are bookmarked in a namespace of their own
+	if msg.Local {
+		cluster, namespace, title = local.Cluster, local.Cluster, "local files"
+	}
+
+	options := defaults.ListModelOptions[tui.Bookmark]{
+		ShowDescription: true,
+		Title:           tui.RenderTitle(title, "bookmarks"),
+		OnEnter: func(selected tui.Bookmark) tea.Msg {
+			// a local file's directory is bookmarked as its pod
+			if selected.Cluster == local.Cluster {
+				path := filepath.Join(selected.Pod, selected.Container)
+				if selected.Pod == "" {
+					path = local.StdinPath
+				}
+
+				return tui.FileLogsViewMsg{
+					Namespace: msg.Namespace,
+					Path:      path,
+					SinceTime: selected.Time,
+				}
+			}
+
+			return tui.ContainerLogsViewMsg{
+				Container: k8s.Container{
+					Namespace: selected.Namespace,
+					Pod:       selected.Pod,
+					Name:      selected.Container,
+				},
+				SinceTime: selected.Time,
+			}
+		},
+		OnEsc: func() tea.Msg {
+			return backMsg{parent: tui.ApisViewMsg{Namespace: msg.Namespace}}
+		},
+		Keys: []defaults.ListKey[tui.Bookmark]{
+			{
+				Binding: remove,
+				Handle: func(selected tui.Bookmark) tea.Msg {
+					if selected.Pod == "" {
+						return nil
+					}
+					if err := store.RemoveBookmark(selected.Bookmark); err != nil {
+						return tui.ErrorMsg{Err: fmt.Errorf("remove bookmark: %w", err)}
+					}
+					return msg
+				},
+			},
+			{
+				Binding: tui.Keys.Export,
+				Handle: func(tui.Bookmark) tea.Msg {
+					path, err := exportTimeline(cluster, namespace)
+					return timelineExportedMsg{path: path, err: err}
+				},
+			},
+		},
+	}
+
+	return bookmarksModel{
+		ListModel: defaults.NewListModel(size, options),
+		title:     title,
+	}
+}
+
+// exportTimeline writes the namespace's bookmarks as a Markdown incident
+// timeline to the working directory
+func exportTimeline(cluster string, namespace string) (string, error) {
+	bookmarks, err := store.LoadBookmarks(cluster, namespace)
+	if err != nil {
+		return "", fmt.Errorf("load bookmarks: %w", err)
+	}
+
+	now := time.Now()
+
+	name := namespace
+	if name == "" {
+		name = "all-namespaces"
+	}
+
+	path := fmt.Sprintf("incident-%s-%s.md", name, now.Format("20060102-150405"))
+	timeline := tui.RenderTimeline(cluster, namespace, bookmarks, now)
+
+	if err := os.WriteFile(path, []byte(timeline), 0o644); err != nil {
+		return "", fmt.Errorf("write timeline: %w", err)
+	}
+
+	return path, nil
+}
+
+func (m bookmarksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(timelineExportedMsg); ok {
+		status := "exported to " + msg.path
+		if msg.err != nil {
+			status = "export failed: " + msg.err.Error()
+		}
+
+		m.SetTitle(tui.RenderTitle(m.title, "bookmarks", status))
+
+		return m, nil
+	}
+
+	lm, cmd := m.ListModel.Update(msg)
+	m.ListModel = lm.(defaults.ListModel[tui.Bookmark])
+	return m, cmd
+}
@@ -1,30 +1,54 @@
 package models
 
 import (
+	"context"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/joshuasprow/log-viewer/k8s"
 	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/pkg"
 	"github.com/joshuasprow/log-viewer/tui"
 )
 
 func ContainerLogs(
+	ctx context.Context,
 	size tea.WindowSizeMsg,
-	container k8s.Container,
-	msgCh chan<- tea.Msg,
+	containers tui.ContainersViewMsg,
+	source pkg.LogSource,
+	since time.Time,
 ) tea.Model {
+	meta := source.Meta()
+	container := metaContainer(meta)
+
 	options := defaults.ListModelOptions[tui.Log]{
-		Title: tui.RenderTitle(
-			container.Namespace,
-			container.Pod,
-			container.Name,
-			"logs",
-		),
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.ContainersViewMsg{
-				Namespace: container.Namespace,
-			}
+		OnEsc: func() tea.Msg {
+			return backMsg{parent: containers}
+		},
+		Keys: []defaults.ListKey[tui.Log]{
+			{
+				Binding: tui.Keys.Split,
+				Handle: func(tui.Log) tea.Msg {
+					compare := containers
+					compare.Compare = container
+					return compare
+				},
+			},
 		},
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	reload := func(since time.Time) tea.Msg {
+		return tui.ContainerLogsViewMsg{
+			Container: container,
+			SinceTime: since,
+		}
+	}
+
+	return newLogsModel(size, logsSource{
+		LogSource: source,
+		since:     since,
+		reload:    reload,
+		lines:     follow(ctx, source),
+		title:     []string{meta.Namespace, meta.Pod, meta.Container},
+		view:      reload(since),
+	}, options)
 }
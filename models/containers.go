@@ -2,28 +2,58 @@ package models
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/k8s"
 	"github.com/joshuasprow/log-viewer/models/defaults"
 	"github.com/joshuasprow/log-viewer/tui"
 )
 
 func Containers(
 	size tea.WindowSizeMsg,
-	namespace string,
-	msgCh chan<- tea.Msg,
+	view tui.ContainersViewMsg,
 ) tea.Model {
+	path := []string{tui.NamespaceTitle(view.Namespace)}
+
+	if view.LabelSelector != "" {
+		path = append(path, "labels: "+view.LabelSelector)
+	}
+	if view.FieldSelector != "" {
+		path = append(path, "fields: "+view.FieldSelector)
+	}
+
 	options := defaults.ListModelOptions[tui.Container]{
-		Title: tui.RenderTitle(namespace, "select a container"),
-		OnEnter: func(selected tui.Container, msgCh chan<- tea.Msg) {
-			msgCh <- tui.ContainerLogsViewMsg{
+		Title: tui.RenderTitle(append(path, "select a container")...),
+		OnEnter: func(selected tui.Container) tea.Msg {
+			return tui.ContainerLogsViewMsg{
 				Container: selected.Container,
 			}
 		},
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.ApisViewMsg{
-				Namespace: namespace,
+		OnEsc: func() tea.Msg {
+			return tui.ApisViewMsg{
+				Namespace: view.Namespace,
 			}
 		},
+		Keys: []defaults.ListKey[tui.Container]{
+			{
+				Binding: tui.Keys.Selector,
+				Handle: func(tui.Container) tea.Msg {
+					return tui.SelectorPromptViewMsg{Containers: view}
+				},
+			},
+		},
+	}
+
+	if compare := view.Compare; compare != (k8s.Container{}) {
+		options.Title = tui.RenderTitle(append(
+			path,
+			"compare "+compare.Pod+"/"+compare.Name+" with",
+		)...)
+		options.OnEnter = func(selected tui.Container) tea.Msg {
+			return tui.SplitViewMsg{Left: compare, Right: selected.Container}
+		}
+		options.OnEsc = func() tea.Msg {
+			return tui.ContainerLogsViewMsg{Container: compare}
+		}
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	return defaults.NewListModel(size, options)
 }
@@ -11,7 +11,7 @@ func CronJobContainers(
 	size tea.WindowSizeMsg,
 	cronJob k8s.CronJob,
 	job k8s.Job,
-	msgCh chan<- tea.Msg,
+	archived bool,
 ) tea.Model {
 	options := defaults.ListModelOptions[tui.Container]{
 		Title: tui.RenderTitle(
@@ -20,17 +20,18 @@ func CronJobContainers(
 			job.Name,
 			"select a container",
 		),
-		OnEnter: func(selected tui.Container, msgCh chan<- tea.Msg) {
-			msgCh <- tui.CronJobLogsViewMsg{
+		OnEnter: func(selected tui.Container) tea.Msg {
+			return tui.CronJobLogsViewMsg{
 				Container: selected.Container,
+				Archived:  archived,
 			}
 		},
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.CronJobJobsViewMsg{
+		OnEsc: func() tea.Msg {
+			return tui.CronJobJobsViewMsg{
 				CronJob: cronJob,
 			}
 		},
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	return defaults.NewListModel(size, options)
 }
@@ -9,8 +9,9 @@ import (
 
 func CronJobJobs(
 	size tea.WindowSizeMsg,
+	namespace string,
 	cronJob k8s.CronJob,
-	msgCh chan<- tea.Msg,
+	compare tui.Job,
 ) tea.Model {
 	options := defaults.ListModelOptions[tui.Job]{
 		ShowDescription: true,
@@ -19,17 +20,62 @@ func CronJobJobs(
 			cronJob.Name,
 			"select a job",
 		),
-		OnEnter: func(selected tui.Job, msgCh chan<- tea.Msg) {
-			msgCh <- tui.CronJobContainersViewMsg{
-				Job: selected.Job,
+		OnEnter: func(selected tui.Job) tea.Msg {
+			return tui.CronJobContainersViewMsg{
+				Job:      selected.Job,
+				Archived: selected.Archived,
 			}
 		},
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.CronJobsViewMsg{
-				Namespace: cronJob.Namespace,
+		OnEsc: func() tea.Msg {
+			return tui.CronJobsViewMsg{
+				Namespace: namespace,
 			}
 		},
+		Keys: []defaults.ListKey[tui.Job]{
+			{
+				Binding: tui.Keys.Split,
+				Handle: func(selected tui.Job) tea.Msg {
+					if selected.Name == "" {
+						return nil
+					}
+					return tui.CronJobJobsViewMsg{
+						CronJob: cronJob,
+						Compare: selected,
+					}
+				},
+			},
+		},
+	}
+
+	if compare != (tui.Job{}) {
+		options.Title = tui.RenderTitle(
+			cronJob.Namespace,
+			cronJob.Name,
+			"compare "+compare.Name+" with",
+		)
+		options.OnEnter = func(selected tui.Job) tea.Msg {
+			if selected.Name == compare.Name {
+				return nil
+			}
+
+			left, right := compare, selected
+			if right.StartTime.Before(left.StartTime) {
+				left, right = right, left
+			}
+
+			return tui.JobDiffViewMsg{
+				CronJob: cronJob,
+				Left:    left,
+				Right:   right,
+			}
+		}
+		options.OnEsc = func() tea.Msg {
+			return tui.CronJobJobsViewMsg{
+				CronJob: cronJob,
+			}
+		}
+		options.Keys = nil
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	return defaults.NewListModel(size, options)
 }
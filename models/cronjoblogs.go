@@ -1,34 +1,57 @@
 package models
 
 import (
+	"context"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joshuasprow/log-viewer/k8s"
 	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/pkg"
 	"github.com/joshuasprow/log-viewer/tui"
 )
 
 func CronJobLogs(
+	ctx context.Context,
 	size tea.WindowSizeMsg,
 	cronJob k8s.CronJob,
 	job k8s.Job,
-	container k8s.Container,
-	msgCh chan<- tea.Msg,
+	archived bool,
+	source pkg.LogSource,
+	since time.Time,
 ) tea.Model {
+	meta := source.Meta()
+	container := metaContainer(meta)
+
 	options := defaults.ListModelOptions[tui.Log]{
-		Title: tui.RenderTitle(
-			cronJob.Namespace,
-			cronJob.Name,
-			job.Name,
-			container.Pod,
-			container.Name,
-			"logs",
-		),
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.CronJobContainersViewMsg{
-				Job: job,
+		OnEsc: func() tea.Msg {
+			return tui.CronJobContainersViewMsg{
+				Job:      job,
+				Archived: archived,
 			}
 		},
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	reload := func(since time.Time) tea.Msg {
+		return tui.CronJobLogsViewMsg{
+			Container: container,
+			SinceTime: since,
+			Archived:  archived,
+		}
+	}
+
+	return newLogsModel(size, logsSource{
+		LogSource: source,
+		since:     since,
+		reload:    reload,
+		lines:     follow(ctx, source),
+		title: []string{
+			cronJob.Namespace,
+			cronJob.Name,
+			job.Name,
+			meta.Pod,
+			meta.Container,
+		},
+		view: reload(since),
+	}, options)
 }
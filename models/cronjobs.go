@@ -9,25 +9,24 @@ import (
 func CronJobs(
 	size tea.WindowSizeMsg,
 	namespace string,
-	msgCh chan<- tea.Msg,
 ) tea.Model {
 	options := defaults.ListModelOptions[tui.CronJob]{
 		ShowDescription: true,
 		Title: tui.RenderTitle(
-			namespace,
+			tui.NamespaceTitle(namespace),
 			"select a cron job",
 		),
-		OnEnter: func(selected tui.CronJob, msgCh chan<- tea.Msg) {
-			msgCh <- tui.CronJobJobsViewMsg{
+		OnEnter: func(selected tui.CronJob) tea.Msg {
+			return tui.CronJobJobsViewMsg{
 				CronJob: selected.CronJob,
 			}
 		},
-		OnEsc: func(msgCh chan<- tea.Msg) {
-			msgCh <- tui.ApisViewMsg{
+		OnEsc: func() tea.Msg {
+			return tui.ApisViewMsg{
 				Namespace: namespace,
 			}
 		},
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	return defaults.NewListModel(size, options)
 }
@@ -1,31 +1,47 @@
 package defaults
 
 import (
+	"slices"
+
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/tui"
 )
 
 type ListModel[ItemType any] struct {
 	model   *list.Model
 	options ListModelOptions[ItemType]
-	msgCh   chan<- tea.Msg
 }
 
+// ListModelOptions callbacks return the message for the view to navigate
+// to. A nil message stays on the current view.
 type ListModelOptions[ItemType any] struct {
-	OnEnter         func(selected ItemType, msgCh chan<- tea.Msg)
-	OnEsc           func(msgCh chan<- tea.Msg)
+	OnEnter         func(selected ItemType) tea.Msg
+	OnEsc           func() tea.Msg
+	Keys            []ListKey[ItemType]
 	ShowDescription bool
-	Title           string
+	// HideHelp leaves the key help to the parent view
+	HideHelp bool
+	Title    string
+	// Filter replaces the fuzzy filter
+	Filter list.FilterFunc
+}
+
+// ListKey adds a view specific key. selected is the zero value when the
+// list is empty. A nil Handle only lists the key in the help, for keys the
+// parent view handles.
+type ListKey[ItemType any] struct {
+	Binding key.Binding
+	Handle  func(selected ItemType) tea.Msg
 }
 
 func NewListModel[ItemType any](
 	size tea.WindowSizeMsg,
 	options ListModelOptions[ItemType],
-	msgCh chan<- tea.Msg,
 ) ListModel[ItemType] {
-	d := &ListItemDelegate{}
+	d := &ListItemDelegate{styles: newListItemStyles()}
 	d.SetShowDescription(options.ShowDescription)
 
 	m := list.New([]list.Item{}, d, 0, 0)
@@ -34,34 +50,42 @@ func NewListModel[ItemType any](
 	m.SetShowStatusBar(false)
 	m.SetSize(size.Width, size.Height)
 	m.SetSpinner(spinner.Dot)
+	m.SetShowHelp(!options.HideHelp)
 	m.Title = options.Title
 
-	// prevents esc as a quit key
-	m.KeyMap.Quit = key.NewBinding(
-		key.WithKeys("q"),
-		key.WithHelp("q", "quit"),
-	)
+	if options.Filter != nil {
+		m.Filter = options.Filter
+	}
+
+	m.KeyMap = newListKeyMap()
 
 	m.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(
-				key.WithKeys("esc"),
-				key.WithHelp("esc", "previous page"),
-			),
+		bindings := []key.Binding{}
+
+		if options.OnEnter != nil {
+			bindings = append(bindings, tui.Keys.Select)
+		}
+		if options.OnEsc != nil {
+			bindings = append(bindings, tui.Keys.Back)
 		}
+		for _, k := range options.Keys {
+			bindings = append(bindings, k.Binding)
+		}
+
+		return bindings
 	}
 
-	m.Styles.NoItems = ListStyles.NoItems
-	m.Styles.HelpStyle = ListStyles.Help
-	m.Styles.PaginationStyle = ListStyles.Pagination
-	m.Styles.Title = ListStyles.Title
-	m.Styles.TitleBar = ListStyles.TitleBar
+	styles := NewListStyles()
+
+	m.Styles.NoItems = styles.NoItems
+	m.Styles.HelpStyle = styles.Help
+	m.Styles.PaginationStyle = styles.Pagination
+	m.Styles.Title = styles.Title
+	m.Styles.TitleBar = styles.TitleBar
 
 	return ListModel[ItemType]{
 		model:   &m,
 		options: options,
-
-		msgCh: msgCh,
 	}
 }
 
@@ -79,16 +103,25 @@ func (m ListModel[ItemType]) Update(
 	case tea.WindowSizeMsg:
 		m.model.SetSize(msg.Width, msg.Height)
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "esc":
+		state := m.model.FilterState()
+
+		switch {
+		case state == list.Filtering:
+			// keys belong to the filter input
+		case key.Matches(msg, tui.Keys.Back) && state == list.Unfiltered:
 			if m.options.OnEsc != nil {
-				m.options.OnEsc(m.msgCh)
-				return m, nil
+				return m, m.options.OnEsc
+			}
+		case key.Matches(msg, tui.Keys.Select):
+			if selected, ok := m.Selected(); ok && m.options.OnEnter != nil {
+				return m, func() tea.Msg { return m.options.OnEnter(selected) }
 			}
-		case "enter":
-			if m.options.OnEnter != nil {
-				m.options.OnEnter(m.Selected(), m.msgCh)
-				return m, nil
+		default:
+			for _, k := range m.options.Keys {
+				if key.Matches(msg, k.Binding) && k.Handle != nil {
+					selected, _ := m.Selected()
+					return m, func() tea.Msg { return k.Handle(selected) }
+				}
 			}
 		}
 	case []list.Item:
@@ -105,6 +138,128 @@ func (m ListModel[ItemType]) View() string {
 	return m.model.View()
 }
 
-func (m ListModel[ItemType]) Selected() ItemType {
-	return m.model.SelectedItem().(ItemType)
+// Append adds items to the end of the list. A cursor on the last item
+// follows the new items.
+func (m ListModel[ItemType]) Append(items ...list.Item) tea.Cmd {
+	following := m.model.Index() >= len(m.model.VisibleItems())-1
+
+	cmd := m.model.SetItems(slices.Concat(m.model.Items(), items))
+	m.model.StopSpinner()
+
+	if following {
+		m.model.Select(len(m.model.VisibleItems()) - 1)
+	}
+
+	return cmd
+}
+
+// SetLast replaces the last item, e.g. when more lines were grouped into it
+func (m ListModel[ItemType]) SetLast(item list.Item) tea.Cmd {
+	n := len(m.model.Items())
+	if n == 0 {
+		return m.Append(item)
+	}
+	return m.model.SetItem(n-1, item)
+}
+
+// ApplyFilter filters the list by term, as if it was typed into the filter
+// and accepted
+func (m ListModel[ItemType]) ApplyFilter(term string) tea.Cmd {
+	m.model.ResetFilter()
+
+	if term == "" || len(m.model.Items()) == 0 {
+		return nil
+	}
+
+	// the list only filters on key presses, so they're simulated with
+	// bindings that match them whatever keys the user configured
+	keys := m.model.KeyMap
+	defer func() {
+		m.model.KeyMap.Filter.SetKeys(keys.Filter.Keys()...)
+		m.model.KeyMap.AcceptWhileFiltering.SetKeys(keys.AcceptWhileFiltering.Keys()...)
+	}()
+
+	m.model.KeyMap.Filter.SetKeys("/")
+	m.model.KeyMap.AcceptWhileFiltering.SetKeys("enter")
+
+	lm, _ := m.model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	lm, cmd := lm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(term)})
+	lm, _ = lm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	*m.model = lm
+
+	return cmd
+}
+
+func (m ListModel[ItemType]) SetTitle(title string) {
+	m.model.Title = title
+}
+
+func (m ListModel[ItemType]) StopSpinner() {
+	m.model.StopSpinner()
+}
+
+// VisibleItems returns the items that match the filter, in the order Index
+// and Select use
+func (m ListModel[ItemType]) VisibleItems() []ItemType {
+	items := []ItemType{}
+	for _, item := range m.model.VisibleItems() {
+		if i, ok := item.(ItemType); ok {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+func (m ListModel[ItemType]) Items() []list.Item {
+	return m.model.Items()
+}
+
+func (m ListModel[ItemType]) SetItems(items []list.Item) tea.Cmd {
+	return m.model.SetItems(items)
+}
+
+func (m ListModel[ItemType]) Index() int {
+	return m.model.Index()
+}
+
+func (m ListModel[ItemType]) Select(index int) {
+	m.model.Select(index)
+}
+
+// CapturingInput reports whether keys are going to the filter input
+func (m ListModel[ItemType]) CapturingInput() bool {
+	return m.model.FilterState() == list.Filtering
+}
+
+func (m ListModel[ItemType]) Selected() (ItemType, bool) {
+	selected, ok := m.model.SelectedItem().(ItemType)
+	return selected, ok
+}
+
+func newListKeyMap() list.KeyMap {
+	k := tui.Keys
+
+	closeHelp := k.Help
+	closeHelp.SetHelp(k.Help.Help().Key, "close help")
+
+	showHelp := k.Help
+	showHelp.SetHelp(k.Help.Help().Key, "more")
+
+	return list.KeyMap{
+		CursorUp:             k.Up,
+		CursorDown:           k.Down,
+		NextPage:             k.NextPage,
+		PrevPage:             k.PrevPage,
+		GoToStart:            k.Start,
+		GoToEnd:              k.End,
+		Filter:               k.Filter,
+		ClearFilter:          k.ClearFilter,
+		CancelWhileFiltering: k.CancelFilter,
+		AcceptWhileFiltering: k.AcceptFilter,
+		ShowFullHelp:         showHelp,
+		CloseFullHelp:        closeHelp,
+		Quit:                 k.Quit,
+		ForceQuit:            k.ForceQuit,
+	}
 }
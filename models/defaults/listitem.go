@@ -7,3 +7,8 @@ type Titled interface {
 type Described interface {
 	Description() string
 }
+
+// Guttered items show a column in front of the title, e.g. a timestamp
+type Guttered interface {
+	Gutter() string
+}
@@ -12,6 +12,7 @@ type ListItemDelegate struct {
 	showDescription bool
 	height          int
 	width           int
+	styles          listItemStyles
 }
 
 func (d *ListItemDelegate) SetShowDescription(b bool) {
@@ -56,16 +57,29 @@ func (d *ListItemDelegate) Render(
 		title = item.FilterValue()
 	}
 
-	if index == m.Index() {
-		title = listItemStyles.SelectedTitle.Render("> " + title)
-	} else {
-		title = listItemStyles.NormalTitle.Render(title)
+	var gutter string
+
+	if g, ok := item.(Guttered); ok {
+		gutter = g.Gutter()
+	}
+
+	switch {
+	case index == m.Index() && gutter != "":
+		// styled separately, so the gutter's colors don't end the
+		// selection's
+		title = d.styles.SelectedTitle.Render("> ") +
+			gutter +
+			d.styles.SelectedText.Render(title)
+	case index == m.Index():
+		title = d.styles.SelectedTitle.Render("> " + title)
+	default:
+		title = d.styles.NormalTitle.Render(gutter + title)
 	}
 
 	var desc string
 
 	if di, ok := item.(Described); d.showDescription && ok {
-		desc = listItemStyles.Description.Render(di.Description())
+		desc = d.styles.Description.Render(di.Description())
 	}
 
 	if desc == "" {
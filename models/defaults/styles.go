@@ -3,25 +3,35 @@ package defaults
 import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/tui"
 )
 
-var listItemStyles = struct {
+type listItemStyles struct {
 	NormalTitle   lipgloss.Style
 	SelectedTitle lipgloss.Style
-	Description   lipgloss.Style
-}{
-	NormalTitle: lipgloss.NewStyle().PaddingLeft(4),
-	SelectedTitle: lipgloss.
-		NewStyle().
-		PaddingLeft(2).
-		Foreground(lipgloss.Color("170")),
-	Description: lipgloss.
-		NewStyle().
-		PaddingLeft(4).
-		Foreground(lipgloss.Color("244")),
+	// SelectedText is SelectedTitle without the padding
+	SelectedText lipgloss.Style
+	Description  lipgloss.Style
 }
 
-var ListStyles = struct {
+func newListItemStyles() listItemStyles {
+	return listItemStyles{
+		NormalTitle: lipgloss.NewStyle().PaddingLeft(4),
+		SelectedTitle: lipgloss.
+			NewStyle().
+			PaddingLeft(2).
+			Foreground(tui.ActiveTheme.Selected),
+		SelectedText: lipgloss.
+			NewStyle().
+			Foreground(tui.ActiveTheme.Selected),
+		Description: lipgloss.
+			NewStyle().
+			PaddingLeft(4).
+			Foreground(tui.ActiveTheme.Muted),
+	}
+}
+
+type ListStyles struct {
 	Help       lipgloss.Style
 	NoItems    lipgloss.Style
 	Pagination lipgloss.Style
@@ -29,12 +39,16 @@ var ListStyles = struct {
 	Spinner    lipgloss.Style
 	Title      lipgloss.Style
 	TitleBar   lipgloss.Style
-}{
-	Help:       list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1),
-	NoItems:    lipgloss.NewStyle().PaddingLeft(4),
-	Pagination: lipgloss.NewStyle().PaddingLeft(4),
-	QuitText:   lipgloss.NewStyle().Margin(1, 0, 2, 4),
-	Spinner:    lipgloss.NewStyle(),
-	Title:      lipgloss.NewStyle().Foreground(lipgloss.Color("205")),
-	TitleBar:   lipgloss.NewStyle().PaddingLeft(4),
+}
+
+func NewListStyles() ListStyles {
+	return ListStyles{
+		Help:       list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1),
+		NoItems:    lipgloss.NewStyle().PaddingLeft(4),
+		Pagination: lipgloss.NewStyle().PaddingLeft(4),
+		QuitText:   lipgloss.NewStyle().Margin(1, 0, 2, 4),
+		Spinner:    lipgloss.NewStyle(),
+		Title:      lipgloss.NewStyle().Foreground(tui.ActiveTheme.ListTitle),
+		TitleBar:   lipgloss.NewStyle().PaddingLeft(4),
+	}
 }
@@ -1,119 +1,309 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/k8s"
 	"github.com/joshuasprow/log-viewer/tui"
 )
 
+const (
+	maxRetries     = 5
+	maxRetryDelay  = 30 * time.Second
+	baseRetryDelay = time.Second
+)
+
 type errorModel struct {
-	size  tea.WindowSizeMsg
-	err   error
-	msgCh chan<- tea.Msg
+	size tea.WindowSizeMsg
+	err  tui.ErrorMsg
+	// back is the view to return to, nil goes back to namespaces
+	back tea.Msg
+	// attempt counts automatic retries of err.Msg so far
+	attempt     int
+	countdown   int
+	showDetails bool
+}
+
+// retryMsg reopens the view that failed. attempt is carried over so
+// automatic retries back off further each time.
+type retryMsg struct {
+	msg     tea.Msg
+	attempt int
+}
+
+type errorTickMsg struct {
+	requestID tui.RequestID
 }
 
 func Error(
 	size tea.WindowSizeMsg,
-	err error,
-	msgCh chan<- tea.Msg,
+	err tui.ErrorMsg,
+	back tea.Msg,
+	attempt int,
 ) tea.Model {
-	return errorModel{
-		size:  size,
-		err:   err,
-		msgCh: msgCh,
+	e := errorModel{
+		size:      size,
+		err:       err,
+		back:      back,
+		attempt:   attempt,
+		countdown: -1,
+	}
+
+	if e.autoRetry() {
+		e.countdown = int(retryDelay(attempt) / time.Second)
+	}
+
+	return e
+}
+
+func retryDelay(attempt int) time.Duration {
+	d := baseRetryDelay << attempt
+	if d > maxRetryDelay || d <= 0 {
+		d = maxRetryDelay
 	}
+	return d
+}
+
+func (e errorModel) autoRetry() bool {
+	return e.err.Msg != nil &&
+		e.attempt < maxRetries &&
+		k8s.IsTransient(e.err.Err)
 }
 
-func (errorModel) Init() tea.Cmd {
-	return nil
+func (e errorModel) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return errorTickMsg{requestID: e.err.RequestID}
+	})
+}
+
+func (e errorModel) Init() tea.Cmd {
+	if e.countdown < 0 {
+		return nil
+	}
+	return e.tick()
+}
+
+func (e errorModel) retry(attempt int) tea.Cmd {
+	return func() tea.Msg {
+		return retryMsg{msg: e.err.Msg, attempt: attempt}
+	}
 }
 
 func (e errorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.size = msg
+	case errorTickMsg:
+		// ticks from an earlier error screen
+		if msg.requestID != e.err.RequestID || e.countdown < 0 {
+			return e, nil
+		}
+
+		e.countdown--
+
+		if e.countdown <= 0 {
+			return e, e.retry(e.attempt + 1)
+		}
+
+		return e, e.tick()
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "ctrl+c", "q":
+		switch {
+		case key.Matches(msg, tui.Keys.Quit, tui.Keys.ForceQuit):
 			return e, tea.Quit
-		case "esc":
-			e.msgCh <- tui.NamespacesViewMsg{}
+		case key.Matches(msg, tui.Keys.Retry) && e.err.Msg != nil:
+			return e, e.retry(0)
+		case key.Matches(msg, tui.Keys.Details):
+			e.showDetails = !e.showDetails
+		case key.Matches(msg, tui.Keys.Back):
+			back := e.back
+			if back == nil {
+				back = tui.NamespacesViewMsg{}
+			}
+			return e, func() tea.Msg { return back }
 		}
 	}
 
 	return e, nil
 }
 
-var errorStyles = struct {
+type errorStyles struct {
 	title   lipgloss.Style
 	message lipgloss.Style
-}{
-	title: lipgloss.
-		NewStyle().
-		PaddingLeft(4).
-		Foreground(lipgloss.Color("#FF0000")),
-	message: lipgloss.
-		NewStyle().
-		PaddingLeft(4),
+	muted   lipgloss.Style
+}
+
+func newErrorStyles(width int) errorStyles {
+	return errorStyles{
+		title: lipgloss.
+			NewStyle().
+			PaddingLeft(4).
+			Foreground(tui.ActiveTheme.Error),
+		message: lipgloss.
+			NewStyle().
+			PaddingLeft(4).
+			Width(width),
+		muted: lipgloss.
+			NewStyle().
+			PaddingLeft(4).
+			Width(width).
+			Foreground(tui.ActiveTheme.Muted),
+	}
+}
+
+// errorChain lists every error wrapped by err, outermost first, with the
+// text each one adds to the error it wraps.
+func errorChain(err error) []string {
+	chain := []string{}
+
+	for depth := 0; err != nil; depth++ {
+		var inner []error
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			if e := u.Unwrap(); e != nil {
+				inner = []error{e}
+			}
+		case interface{ Unwrap() []error }:
+			inner = u.Unwrap()
+		}
+
+		text := err.Error()
+
+		if len(inner) == 1 {
+			text = strings.TrimSuffix(text, inner[0].Error())
+			text = strings.TrimSuffix(strings.TrimSpace(text), ":")
+		}
+
+		chain = append(chain, fmt.Sprintf(
+			"%s%T: %s",
+			strings.Repeat("  ", depth),
+			err,
+			text,
+		))
+
+		if len(inner) != 1 {
+			for _, e := range inner {
+				for _, line := range errorChain(e) {
+					chain = append(chain, strings.Repeat("  ", depth+1)+line)
+				}
+			}
+			break
+		}
+
+		err = inner[0]
+	}
+
+	return chain
 }
 
 func (e errorModel) View() string {
-	title := errorStyles.title.Render("error")
-	message := errorStyles.message.Render(e.err.Error())
-	help := newErrorHelpView()
+	styles := newErrorStyles(e.size.Width - 4)
+
+	message := e.err.Err.Error()
+
+	mpe := k8s.MissingPermissionsError{}
+	isMissing := errors.As(e.err.Err, &mpe)
+
+	// the permissions are listed below, so the message is only the error
+	// they explain
+	if isMissing {
+		message = mpe.Err.Error()
+	}
+
+	sections := []string{
+		styles.title.Render(string(k8s.Classify(e.err.Err))),
+		styles.message.Render(message),
+	}
+
+	if isMissing {
+		missing := []string{"", "missing permissions:"}
+		for _, p := range mpe.Missing {
+			missing = append(missing, "  🚫 "+p.String())
+		}
+		sections = append(sections, styles.message.Render(strings.Join(missing, "\n")))
+	}
+
+	if e.countdown > 0 {
+		sections = append(sections, "", styles.muted.Render(fmt.Sprintf(
+			"retrying in %ds (attempt %d/%d)",
+			e.countdown,
+			e.attempt+1,
+			maxRetries,
+		)))
+	}
+
+	if e.showDetails {
+		sections = append(
+			sections,
+			"",
+			styles.muted.Render(strings.Join(errorChain(e.err.Err), "\n")),
+		)
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	help := newErrorHelpView(e.err.Msg != nil)
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		title,
-		message,
-		renderErrorHelpView(e.size.Height, title, message, help),
+		body,
+		renderErrorHelpView(e.size.Height, body, help),
 	)
 }
 
 type errorModelKeyMap struct {
-	back key.Binding
-	quit key.Binding
+	back    key.Binding
+	retry   key.Binding
+	details key.Binding
+	quit    key.Binding
 }
 
-func newErrorModelKeyMap() errorModelKeyMap {
+func newErrorModelKeyMap(canRetry bool) errorModelKeyMap {
+	back := tui.Keys.Back
+	back.SetHelp(back.Help().Key, "back")
+
+	retry := tui.Keys.Retry
+	retry.SetEnabled(canRetry)
+
 	return errorModelKeyMap{
-		back: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "back"),
-		),
-		quit: key.NewBinding(
-			key.WithKeys("ctrl+c", "q"),
-			key.WithHelp("q", "quit"),
-		),
+		back:    back,
+		retry:   retry,
+		details: tui.Keys.Details,
+		quit:    tui.Keys.Quit,
 	}
 }
 
 func (m errorModelKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{m.back, m.quit}
+	return []key.Binding{m.back, m.retry, m.details, m.quit}
 }
 
 func (m errorModelKeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{{m.back, m.quit}}
+	return [][]key.Binding{m.ShortHelp()}
 }
 
-func newErrorHelpView() string {
-	return help.New().View(newErrorModelKeyMap())
+func newErrorHelpView(canRetry bool) string {
+	return help.New().View(newErrorModelKeyMap(canRetry))
 }
 
 func renderErrorHelpView(
 	height int,
-	title string,
-	message string,
+	body string,
 	help string,
 ) string {
 	return lipgloss.
 		NewStyle().
-		MarginTop(
-			height -
-				lipgloss.Height(title) -
-				lipgloss.Height(message) -
+		MarginTop(max(
+			height-
+				lipgloss.Height(body)-
 				lipgloss.Height(help),
-		).
+			0,
+		)).
 		PaddingLeft(4).
 		Render(help)
 }
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/local"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+func FileLogs(
+	ctx context.Context,
+	size tea.WindowSizeMsg,
+	msg tui.FileLogsViewMsg,
+	source pkg.LogSource,
+) tea.Model {
+	options := defaults.ListModelOptions[tui.Log]{
+		OnEsc: func() tea.Msg {
+			return backMsg{parent: tui.FilesViewMsg{Namespace: msg.Namespace}}
+		},
+	}
+
+	// stdin is all there is to view when it's piped in
+	if msg.Path == local.StdinPath {
+		options.OnEsc = nil
+	}
+
+	return newLogsModel(size, logsSource{
+		LogSource: source,
+		since:     msg.SinceTime,
+		lines:     follow(ctx, source),
+		view:      msg,
+	}, options)
+}
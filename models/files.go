@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+// Files lists local log files. without a cluster there's nothing to go back
+// to.
+func Files(
+	size tea.WindowSizeMsg,
+	namespace string,
+	clusterless bool,
+) tea.Model {
+	options := defaults.ListModelOptions[tui.File]{
+		ShowDescription: true,
+		Title:           tui.RenderTitle("local files", "select a file"),
+		OnEnter: func(selected tui.File) tea.Msg {
+			return tui.FileLogsViewMsg{
+				Namespace: namespace,
+				Path:      selected.Path,
+			}
+		},
+		Keys: []defaults.ListKey[tui.File]{
+			{
+				Binding: bookmarksKey(),
+				Handle: func(tui.File) tea.Msg {
+					return tui.BookmarksViewMsg{Namespace: namespace, Local: true}
+				},
+			},
+		},
+	}
+
+	if !clusterless {
+		options.OnEsc = func() tea.Msg {
+			return tui.ApisViewMsg{
+				Namespace: namespace,
+			}
+		}
+	}
+
+	return defaults.NewListModel(size, options)
+}
+
+func bookmarksKey() key.Binding {
+	k := tui.Keys.Bookmark
+	k.SetHelp(k.Help().Key, "bookmarks")
+	return k
+}
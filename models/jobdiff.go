@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/diff"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+// jobDiffModel shows the diff of two runs' logs, starting where they first
+// diverge
+type jobDiffModel struct {
+	defaults.ListModel[tui.DiffLine]
+	msg tui.JobDiffViewMsg
+}
+
+func JobDiff(size tea.WindowSizeMsg, msg tui.JobDiffViewMsg) tea.Model {
+	m := jobDiffModel{msg: msg}
+
+	options := defaults.ListModelOptions[tui.DiffLine]{
+		Title: m.title("comparing"),
+		OnEsc: func() tea.Msg {
+			return tui.CronJobJobsViewMsg{
+				CronJob: msg.CronJob,
+			}
+		},
+	}
+
+	m.ListModel = defaults.NewListModel(size, options)
+
+	return m
+}
+
+func (m jobDiffModel) title(status string) string {
+	return tui.RenderTitle(
+		m.msg.CronJob.Namespace,
+		m.msg.CronJob.Name,
+		m.msg.Left.Name+" → "+m.msg.Right.Name,
+		status,
+	)
+}
+
+func (m jobDiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	lm, cmd := m.ListModel.Update(msg)
+	m.ListModel = lm.(defaults.ListModel[tui.DiffLine])
+
+	if items, ok := msg.([]list.Item); ok {
+		lines := make([]diff.Line, 0, len(items))
+		for _, item := range items {
+			if l, ok := item.(tui.DiffLine); ok {
+				lines = append(lines, l.Line)
+			}
+		}
+
+		m.SetTitle(m.title(summary(lines)))
+
+		if first := diff.FirstChange(lines); first >= 0 {
+			m.Select(first)
+		}
+	}
+
+	return m, cmd
+}
+
+// summary counts the changed lines and says where the runs first diverge
+func summary(lines []diff.Line) string {
+	added, removed := 0, 0
+
+	for _, l := range lines {
+		switch l.Op {
+		case diff.Added:
+			added++
+		case diff.Removed:
+			removed++
+		}
+	}
+
+	first := diff.FirstChange(lines)
+	if first < 0 {
+		return "no differences"
+	}
+
+	return fmt.Sprintf(
+		"+%d -%d, first diverges after %d matching lines",
+		added,
+		removed,
+		first,
+	)
+}
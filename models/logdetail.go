@@ -0,0 +1,417 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/tui"
+	"github.com/muesli/termenv"
+)
+
+// closeDetailMsg closes the detail modal. a filter is applied to the log
+// list.
+type closeDetailMsg struct {
+	filter string
+}
+
+type detailCopiedMsg struct {
+	status string
+}
+
+// detailRow is a line of the record tree
+type detailRow struct {
+	// path is the dotted path of the field, "" for the record itself
+	path  string
+	depth int
+	node  tui.Node
+	// inArray rows are shown without their key
+	inArray bool
+	last    bool
+	// closer rows end an expanded object or array
+	closer bool
+}
+
+// logDetailModel shows a log entry with its parsed record as a tree, or as
+// a table for flat records
+type logDetailModel struct {
+	size      tea.WindowSizeMsg
+	container k8s.Container
+	entry     tui.Log
+	record    tui.Node
+	parsed    bool
+	table     bool
+	collapsed map[string]bool
+	cursor    int
+	offset    int
+	status    string
+}
+
+func newLogDetailModel(
+	size tea.WindowSizeMsg,
+	container k8s.Container,
+	entry tui.Log,
+) logDetailModel {
+	record, parsed := tui.ParseRecord(entry.Text, tui.ParserFormat(container))
+
+	table := parsed
+	for _, c := range record.Children {
+		if c.Kind == tui.ObjectNode || c.Kind == tui.ArrayNode {
+			table = false
+		}
+	}
+
+	return logDetailModel{
+		size:      size,
+		container: container,
+		entry:     entry,
+		record:    record,
+		parsed:    parsed,
+		table:     table,
+		collapsed: map[string]bool{},
+	}
+}
+
+func (m logDetailModel) rows() []detailRow {
+	if !m.parsed {
+		return nil
+	}
+
+	if m.table {
+		rows := make([]detailRow, len(m.record.Children))
+		for i, c := range m.record.Children {
+			rows[i] = detailRow{path: c.Key, node: c}
+		}
+		return rows
+	}
+
+	return m.appendRows(nil, detailRow{node: m.record, last: true})
+}
+
+func (m logDetailModel) appendRows(rows []detailRow, row detailRow) []detailRow {
+	rows = append(rows, row)
+
+	n := row.node
+	if (n.Kind != tui.ObjectNode && n.Kind != tui.ArrayNode) || m.collapsed[row.path] {
+		return rows
+	}
+
+	for i, c := range n.Children {
+		path := c.Key
+		if row.path != "" {
+			path = row.path + "." + c.Key
+		}
+
+		rows = m.appendRows(rows, detailRow{
+			path:    path,
+			depth:   row.depth + 1,
+			node:    c,
+			inArray: n.Kind == tui.ArrayNode,
+			last:    i == len(n.Children)-1,
+		})
+	}
+
+	closer := row
+	closer.closer = true
+
+	return append(rows, closer)
+}
+
+// bodyHeight is the number of rows that fit between the title and the help
+func (m logDetailModel) bodyHeight() int {
+	return max(m.size.Height-4, 1)
+}
+
+// move moves the cursor by step rows, skipping closers
+func (m logDetailModel) move(step int) logDetailModel {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return m
+	}
+
+	i := max(min(m.cursor+step, len(rows)-1), 0)
+
+	dir := 1
+	if step < 0 {
+		dir = -1
+	}
+
+	for rows[i].closer {
+		if i+dir < 0 || i+dir >= len(rows) {
+			dir = -dir
+		}
+		i += dir
+	}
+
+	m.cursor = i
+
+	height := m.bodyHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+
+	return m
+}
+
+func (m logDetailModel) selected() (detailRow, bool) {
+	rows := m.rows()
+	if m.cursor >= len(rows) {
+		return detailRow{}, false
+	}
+	return rows[m.cursor], true
+}
+
+func (m logDetailModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m logDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+		return m.move(0), nil
+	case detailCopiedMsg:
+		m.status = msg.status
+		return m, nil
+	case tea.KeyMsg:
+		m.status = ""
+
+		switch {
+		case key.Matches(msg, tui.Keys.ForceQuit, tui.Keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.Back):
+			return m, func() tea.Msg { return closeDetailMsg{} }
+		case key.Matches(msg, tui.Keys.Up):
+			return m.move(-1), nil
+		case key.Matches(msg, tui.Keys.Down):
+			return m.move(1), nil
+		case key.Matches(msg, tui.Keys.PrevPage):
+			return m.move(-m.bodyHeight()), nil
+		case key.Matches(msg, tui.Keys.NextPage):
+			return m.move(m.bodyHeight()), nil
+		case key.Matches(msg, tui.Keys.Start):
+			return m.move(-len(m.rows())), nil
+		case key.Matches(msg, tui.Keys.End):
+			return m.move(len(m.rows())), nil
+		case key.Matches(msg, tui.Keys.Select, tui.Keys.Expand):
+			row, ok := m.selected()
+			if !ok || row.path == "" {
+				return m, nil
+			}
+			if row.node.Kind == tui.ObjectNode || row.node.Kind == tui.ArrayNode {
+				m.collapsed[row.path] = !m.collapsed[row.path]
+			}
+			return m.move(0), nil
+		case key.Matches(msg, tui.Keys.Copy):
+			value := m.entry.Text
+			if row, ok := m.selected(); ok && row.path != "" {
+				value = row.node.String()
+			}
+			return m, copyValue(value)
+		case key.Matches(msg, tui.Keys.FilterField):
+			row, ok := m.selected()
+			if !ok || row.path == "" {
+				m.status = "select a field to filter on"
+				return m, nil
+			}
+			filter := tui.FieldFilter(row.path, row.node)
+			return m, func() tea.Msg { return closeDetailMsg{filter: filter} }
+		}
+	}
+
+	return m, nil
+}
+
+// copyValue copies to the system clipboard, falling back to the terminal's
+// clipboard (OSC 52), e.g. over ssh
+func copyValue(value string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(value); err != nil {
+			termenv.Copy(value)
+			return detailCopiedMsg{status: "copied via the terminal"}
+		}
+		return detailCopiedMsg{status: "copied"}
+	}
+}
+
+type detailStyles struct {
+	key      lipgloss.Style
+	str      lipgloss.Style
+	literal  lipgloss.Style
+	muted    lipgloss.Style
+	selected lipgloss.Style
+}
+
+func newDetailStyles() detailStyles {
+	return detailStyles{
+		key:      lipgloss.NewStyle().Foreground(tui.ActiveTheme.TitlePath),
+		str:      lipgloss.NewStyle().Foreground(tui.ActiveTheme.TitleCurrent),
+		literal:  lipgloss.NewStyle().Foreground(tui.ActiveTheme.ListTitle),
+		muted:    lipgloss.NewStyle().Foreground(tui.ActiveTheme.Muted),
+		selected: lipgloss.NewStyle().Foreground(tui.ActiveTheme.Selected),
+	}
+}
+
+func (s detailStyles) value(n tui.Node) string {
+	switch n.Kind {
+	case tui.StringNode:
+		return s.str.Render(strconv.Quote(n.Value))
+	case tui.NullNode:
+		return s.muted.Render(n.Value)
+	default:
+		return s.literal.Render(n.Value)
+	}
+}
+
+func (m logDetailModel) renderRow(s detailStyles, row detailRow, keyWidth int) string {
+	n := row.node
+
+	if m.table {
+		return s.key.Render(padRight(row.path, keyWidth)) + "  " + n.Value
+	}
+
+	open, close := "{", "}"
+	if n.Kind == tui.ArrayNode {
+		open, close = "[", "]"
+	}
+
+	comma := ""
+	if !row.last {
+		comma = ","
+	}
+
+	indent := strings.Repeat("  ", row.depth)
+
+	if row.closer {
+		return indent + close + comma
+	}
+
+	line := indent
+	if row.path != "" && !row.inArray {
+		line += s.key.Render(strconv.Quote(n.Key)) + ": "
+	}
+
+	switch {
+	case n.Kind != tui.ObjectNode && n.Kind != tui.ArrayNode:
+		line += s.value(n) + comma
+	case m.collapsed[row.path]:
+		size := fmt.Sprintf("%d keys", len(n.Children))
+		if n.Kind == tui.ArrayNode {
+			size = fmt.Sprintf("%d items", len(n.Children))
+		}
+		line += open + "…" + close + comma + " " + s.muted.Render(size)
+	default:
+		line += open
+	}
+
+	return line
+}
+
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+}
+
+func (m logDetailModel) View() string {
+	s := newDetailStyles()
+	width := max(m.size.Width-4, 1)
+	height := m.bodyHeight()
+
+	path := []string{m.container.Name}
+	if m.container.Pod != "" {
+		path = append([]string{m.container.Pod}, path...)
+	}
+	if !m.entry.Time.IsZero() {
+		path = append(path, tui.FormatTime(m.entry.Time))
+	}
+	title := tui.RenderTitle(path...)
+
+	lines := []string{}
+
+	if rows := m.rows(); len(rows) > 0 {
+		keyWidth := 0
+		for _, row := range rows {
+			keyWidth = max(keyWidth, len(row.path))
+		}
+
+		for i := m.offset; i < min(m.offset+height, len(rows)); i++ {
+			line := m.renderRow(s, rows[i], keyWidth)
+
+			if i == m.cursor {
+				line = s.selected.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+
+			lines = append(lines, line)
+		}
+	} else {
+		// plain entries are wrapped instead of cut off like in the list
+		text := strings.ReplaceAll(m.entry.Text, "\t", "    ")
+		wrapped := lipgloss.NewStyle().Width(width - 2).Render(text)
+		all := strings.Split(wrapped, "\n")
+		lines = all[:min(len(all), height)]
+
+		for i := range lines {
+			lines[i] = "  " + lines[i]
+		}
+	}
+
+	body := lipgloss.
+		NewStyle().
+		Height(height).
+		MaxWidth(width).
+		Render(strings.Join(lines, "\n"))
+
+	footer := help.New().View(newDetailKeyMap(m.parsed))
+	if m.status != "" {
+		footer = s.muted.Render(m.status)
+	}
+
+	return lipgloss.
+		NewStyle().
+		PaddingLeft(2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", body, footer))
+}
+
+type detailKeyMap struct {
+	toggle key.Binding
+	copy   key.Binding
+	filter key.Binding
+	back   key.Binding
+}
+
+func newDetailKeyMap(parsed bool) detailKeyMap {
+	toggle := tui.Keys.Select
+	toggle.SetHelp(toggle.Help().Key, "collapse")
+	toggle.SetEnabled(parsed)
+
+	filter := tui.Keys.FilterField
+	filter.SetEnabled(parsed)
+
+	back := tui.Keys.Back
+	back.SetHelp(back.Help().Key, "close")
+
+	return detailKeyMap{
+		toggle: toggle,
+		copy:   tui.Keys.Copy,
+		filter: filter,
+		back:   back,
+	}
+}
+
+func (k detailKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.toggle, k.copy, k.filter, k.back}
+}
+
+func (k detailKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
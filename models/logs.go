@@ -0,0 +1,763 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/local"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/store"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+type bookmarksLoadedMsg []store.Bookmark
+
+type logsExportedMsg struct {
+	path string
+	err  error
+}
+
+const (
+	// timelineBars is the height of the timeline's bars, which sit over a
+	// line of times
+	timelineBars = 3
+	// minTimelineWidth fits the times under the timeline
+	minTimelineWidth = 40
+)
+
+type logsPrompt int
+
+const (
+	noPrompt logsPrompt = iota
+	notePrompt
+	timePrompt
+)
+
+// logsSource describes where the lines of a log view come from
+type logsSource struct {
+	pkg.LogSource
+	// since is the time the lines were loaded from, or zero for the tail
+	since time.Time
+	// reload asks for the view again with the history since a time
+	reload func(since time.Time) tea.Msg
+	// lines streams the lines of a followed source, e.g. a local file.
+	// otherwise they're loaded for the view.
+	lines <-chan pkg.Result[string]
+	// title is the path the stream's status is shown after, the source's
+	// pod and container when it's empty
+	title []string
+	// view is the view's message, which the error screen opens again when
+	// the source fails
+	view tea.Msg
+}
+
+// follow streams the lines of source until ctx is done, then closes it
+func follow(ctx context.Context, source pkg.LogSource) <-chan pkg.Result[string] {
+	context.AfterFunc(ctx, func() {
+		if err := source.Close(); err != nil {
+			log.Printf("close log source: %v\n", err)
+		}
+	})
+
+	return source.Follow(ctx)
+}
+
+// sourceFailed shows the error screen for a log view whose source failed,
+// which retries the view or goes back from it
+func sourceFailed(view tea.Msg, err error) tea.Cmd {
+	return func() tea.Msg {
+		return tui.ErrorMsg{Msg: view, Err: err}
+	}
+}
+
+// metaContainer is the container a source's lines are bookmarked under
+func metaContainer(meta pkg.LogMeta) k8s.Container {
+	return k8s.Container{
+		Namespace: meta.Namespace,
+		Pod:       meta.Pod,
+		Name:      meta.Container,
+	}
+}
+
+// metaTitle names the pod and container of a source, or just the
+// container when there's no pod, e.g. for stdin
+func metaTitle(meta pkg.LogMeta) []string {
+	if meta.Pod == "" {
+		return []string{meta.Container}
+	}
+	return []string{meta.Pod, meta.Container}
+}
+
+// logsModel is a log list whose lines can be bookmarked and searched by
+// time
+type logsModel struct {
+	size      tea.WindowSizeMsg
+	list      defaults.ListModel[tui.Log]
+	source    logsSource
+	bookmarks []store.Bookmark
+
+	// expanded shows all lines of the selected entry under the list
+	expanded bool
+	// detail is the modal showing the parsed record of an entry, if open
+	detail *logDetailModel
+	// timeline shows the histogram of the entries over time above the list,
+	// once timed says there are entries with times
+	timeline bool
+	timed    bool
+
+	// prompt is the input shown under the list, if any
+	prompt    logsPrompt
+	input     textinput.Model
+	promptErr string
+
+	// last is the latest streamed entry, which later lines may continue
+	last tui.Log
+	// seeking is set until a stream from a time has sent lines from then
+	seeking bool
+	closed  bool
+	paused  bool
+	// exported is the status of the last export, shown after the stream's
+	exported string
+}
+
+func newLogsModel(
+	size tea.WindowSizeMsg,
+	source logsSource,
+	options defaults.ListModelOptions[tui.Log],
+) logsModel {
+	options.Filter = tui.FilterLogs(tui.ParserFormat(metaContainer(source.Meta())))
+	options.Keys = append(
+		options.Keys,
+		defaults.ListKey[tui.Log]{Binding: detailKey()},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.Bookmark},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.NextBookmark},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.PrevBookmark},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.TimeGutter},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.GoToTime},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.Expand},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.Timeline},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.TimelinePrev},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.TimelineNext},
+		defaults.ListKey[tui.Log]{Binding: tui.Keys.Export},
+	)
+
+	if _, ok := source.LogSource.(pkg.LogPlayer); ok {
+		options.Keys = append(options.Keys, defaults.ListKey[tui.Log]{Binding: tui.Keys.Pause})
+	}
+
+	m := logsModel{
+		size:     size,
+		list:     defaults.NewListModel(size, options),
+		source:   source,
+		timeline: true,
+		seeking:  source.lines != nil && !source.since.IsZero(),
+	}
+
+	if source.lines != nil {
+		m.list.SetTitle(m.streamTitle())
+	}
+
+	return m
+}
+
+func (m logsModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{m.list.Init(), func() tea.Msg {
+		meta := m.source.Meta()
+
+		bookmarks, err := store.LoadBookmarks(meta.Cluster, meta.Namespace)
+		if err != nil {
+			log.Printf("load bookmarks: %v\n", err)
+		}
+		return bookmarksLoadedMsg(bookmarks)
+	}}
+
+	if m.source.lines != nil {
+		cmds = append(cmds, waitForLines(m.source.lines))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m logsModel) streamTitle() string {
+	status := "following"
+	if _, ok := m.source.LogSource.(pkg.LogPlayer); ok {
+		status = "replaying"
+	}
+
+	switch {
+	case m.closed:
+		status = "ended"
+	case m.paused:
+		status = "paused"
+	}
+
+	title := m.source.title
+	if len(title) == 0 {
+		title = metaTitle(m.source.Meta())
+	}
+
+	title = append(slices.Clone(title), status)
+	if m.exported != "" {
+		title = append(title, m.exported)
+	}
+
+	return tui.RenderTitle(title...)
+}
+
+func (m logsModel) CapturingInput() bool {
+	return m.prompt != noPrompt || m.detail != nil || m.list.CapturingInput()
+}
+
+func detailKey() key.Binding {
+	k := tui.Keys.Select
+	k.SetHelp(k.Help().Key, "details")
+	return k
+}
+
+func (m logsModel) bookmarkOf(l tui.Log) int {
+	meta := m.source.Meta()
+
+	return slices.IndexFunc(m.bookmarks, func(b store.Bookmark) bool {
+		return b.Marks(meta.Pod, meta.Container, l.Time, l.Text)
+	})
+}
+
+// mark flags the lines that are bookmarked
+func (m logsModel) mark() tea.Cmd {
+	items := m.list.Items()
+	marked := make([]list.Item, len(items))
+
+	for i, item := range items {
+		if l, ok := item.(tui.Log); ok {
+			l.Bookmarked = m.bookmarkOf(l) >= 0
+			item = l
+		}
+		marked[i] = item
+	}
+
+	return m.list.SetItems(marked)
+}
+
+// jump selects the next bookmarked line in direction step, wrapping around
+func (m logsModel) jump(step int) {
+	items := m.list.VisibleItems()
+
+	for n := 1; n <= len(items); n++ {
+		i := (m.list.Index() + n*step + len(items)*n) % len(items)
+
+		if items[i].Bookmarked {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// expandedHeight is the height of the panel showing the selected entry
+func (m logsModel) expandedHeight() int {
+	if !m.expanded {
+		return 0
+	}
+	return max(m.size.Height/3, 3)
+}
+
+// timelineHeight is the height of the timeline above the list
+func (m logsModel) timelineHeight() int {
+	if !m.timeline || !m.timed || m.timelineWidth() < minTimelineWidth {
+		return 0
+	}
+	return timelineBars + 1
+}
+
+func (m logsModel) timelineWidth() int {
+	return m.size.Width - 8
+}
+
+// histogram buckets every loaded entry, whatever the filter, a bucket per
+// column
+func (m logsModel) histogram() (tui.Histogram, bool) {
+	items := m.list.Items()
+	logs := make([]tui.Log, 0, len(items))
+
+	for _, item := range items {
+		if l, ok := item.(tui.Log); ok {
+			logs = append(logs, l)
+		}
+	}
+
+	return tui.NewHistogram(logs, m.timelineWidth())
+}
+
+// cursor is the bucket of the selected entry, or of the closest one above
+// it that has a time
+func (m logsModel) cursor(h tui.Histogram) int {
+	items := m.list.VisibleItems()
+
+	for i := min(m.list.Index(), len(items)-1); i >= 0; i-- {
+		if !items[i].Time.IsZero() {
+			return h.Bucket(items[i].Time)
+		}
+	}
+
+	return -1
+}
+
+// moveOnTimeline selects the first entry of the closest bucket with entries
+// in direction step
+func (m logsModel) moveOnTimeline(step int) {
+	h, ok := m.histogram()
+	if !ok {
+		return
+	}
+
+	bucket := h.Nearest(m.cursor(h), step)
+	if bucket < 0 {
+		return
+	}
+
+	for i, l := range m.list.VisibleItems() {
+		if !l.Time.IsZero() && h.Bucket(l.Time) >= bucket {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// retime notes whether there are entries with times yet, and makes room for
+// the timeline once there are
+func (m logsModel) retime() logsModel {
+	if m.timed {
+		return m
+	}
+
+	for _, item := range m.list.Items() {
+		if l, ok := item.(tui.Log); ok && !l.Time.IsZero() {
+			m.timed = true
+			return m.layout()
+		}
+	}
+
+	return m
+}
+
+// layout fits the list between the timeline, and the expanded entry and
+// the prompt
+func (m logsModel) layout() logsModel {
+	height := m.size.Height - m.expandedHeight() - m.timelineHeight()
+	if m.prompt != noPrompt {
+		height--
+	}
+
+	lm, _ := m.list.Update(tea.WindowSizeMsg{Width: m.size.Width, Height: height})
+	m.list = lm.(defaults.ListModel[tui.Log])
+	return m
+}
+
+func (m logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+		if m.detail != nil {
+			d, _ := m.detail.Update(msg)
+			m.detail = pkg.Ptr(d.(logDetailModel))
+		}
+		return m.layout(), nil
+	case closeDetailMsg:
+		m.detail = nil
+		return m, m.list.ApplyFilter(msg.filter)
+	case detailCopiedMsg:
+		if m.detail == nil {
+			return m, nil
+		}
+		d, cmd := m.detail.Update(msg)
+		m.detail = pkg.Ptr(d.(logDetailModel))
+		return m, cmd
+	case logsExportedMsg:
+		m.exported = "exported to " + msg.path
+		if msg.err != nil {
+			m.exported = "export failed: " + msg.err.Error()
+		}
+		m.list.SetTitle(m.streamTitle())
+		return m, nil
+	case bookmarksLoadedMsg:
+		m.bookmarks = msg
+		return m, m.mark()
+	case []list.Item:
+		lm, cmd := m.list.Update(msg)
+		m.list = lm.(defaults.ListModel[tui.Log])
+
+		// lines loaded for a go to time start at that time
+		if !m.source.since.IsZero() {
+			m.seek(m.source.since)
+		}
+
+		return m.retime(), tea.Batch(cmd, m.mark())
+	case linesMsg:
+		if msg.ch != m.source.lines {
+			return m, nil
+		}
+		return m.appendLines(msg)
+	case tea.KeyMsg:
+		if m.detail != nil {
+			d, cmd := m.detail.Update(msg)
+			m.detail = pkg.Ptr(d.(logDetailModel))
+			return m, cmd
+		}
+		if m.prompt != noPrompt {
+			return m.updatePrompt(msg)
+		}
+		if m.list.CapturingInput() {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, tui.Keys.Bookmark):
+			return m.toggleBookmark()
+		case key.Matches(msg, tui.Keys.NextBookmark):
+			m.jump(1)
+			return m, nil
+		case key.Matches(msg, tui.Keys.PrevBookmark):
+			m.jump(-1)
+			return m, nil
+		case key.Matches(msg, tui.Keys.Export):
+			meta := m.source.Meta()
+			logs := m.list.VisibleItems()
+			return m, func() tea.Msg {
+				path, err := exportLogs(meta, logs, time.Now())
+				return logsExportedMsg{path: path, err: err}
+			}
+		case key.Matches(msg, tui.Keys.GoToTime):
+			return m.openPrompt(timePrompt, "go to: ", "15:04, -15m, start")
+		case key.Matches(msg, tui.Keys.Select):
+			selected, ok := m.list.Selected()
+			if !ok {
+				return m, nil
+			}
+			container := metaContainer(m.source.Meta())
+			m.detail = pkg.Ptr(newLogDetailModel(m.size, container, selected))
+			return m, nil
+		case key.Matches(msg, tui.Keys.Expand):
+			m.expanded = !m.expanded
+			return m.layout(), nil
+		case key.Matches(msg, tui.Keys.Timeline):
+			m.timeline = !m.timeline
+			return m.layout(), nil
+		case key.Matches(msg, tui.Keys.TimelinePrev):
+			m.moveOnTimeline(-1)
+			return m, nil
+		case key.Matches(msg, tui.Keys.TimelineNext):
+			m.moveOnTimeline(1)
+			return m, nil
+		case key.Matches(msg, tui.Keys.Pause):
+			player, ok := m.source.LogSource.(pkg.LogPlayer)
+			if !ok || m.closed {
+				return m, nil
+			}
+			m.paused = player.TogglePause()
+			m.list.SetTitle(m.streamTitle())
+			return m, nil
+		}
+	}
+
+	if m.prompt != noPrompt {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	lm, cmd := m.list.Update(msg)
+	m.list = lm.(defaults.ListModel[tui.Log])
+	return m, cmd
+}
+
+func (m logsModel) appendLines(msg linesMsg) (tea.Model, tea.Cmd) {
+	lines := make([]string, 0, len(msg.results))
+	for _, r := range msg.results {
+		if r.Err != nil {
+			return m, sourceFailed(m.source.view, r.Err)
+		}
+		lines = append(lines, r.V)
+	}
+
+	var cmd tea.Cmd
+	m.last, cmd = appendLines(m.list, m.last, lines)
+
+	// a stream from a time shows that time rather than its end, once it
+	// got there
+	if m.seeking && !m.last.Time.Before(m.source.since) {
+		m.seeking = false
+		m.seek(m.source.since)
+	}
+
+	cmds := []tea.Cmd{cmd}
+
+	if msg.closed {
+		m.closed = true
+		m.list.StopSpinner()
+	} else {
+		cmds = append(cmds, waitForLines(m.source.lines))
+	}
+
+	// new lines may have been bookmarked in an earlier session
+	if len(m.bookmarks) > 0 {
+		cmds = append(cmds, m.mark())
+	}
+
+	m.list.SetTitle(m.streamTitle())
+
+	return m.retime(), tea.Batch(cmds...)
+}
+
+// exportLogs writes the entries to the working directory, each line after
+// its entry's timestamp like the API sends them, so the file can be opened
+// again with its times
+func exportLogs(meta pkg.LogMeta, logs []tui.Log, now time.Time) (string, error) {
+	name := meta.Container
+	if meta.Cluster != local.Cluster && meta.Pod != "" {
+		name = meta.Pod + "-" + meta.Container
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, name)
+
+	var b strings.Builder
+
+	for _, l := range logs {
+		for _, line := range l.Lines() {
+			if !l.Time.IsZero() {
+				b.WriteString(l.Time.Format(time.RFC3339Nano) + " ")
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	path := fmt.Sprintf("logs-%s-%s.log", name, now.Format("20060102-150405"))
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", fmt.Errorf("write logs: %w", err)
+	}
+
+	return path, nil
+}
+
+func (m logsModel) toggleBookmark() (tea.Model, tea.Cmd) {
+	selected, ok := m.list.Selected()
+	if !ok {
+		return m, nil
+	}
+
+	if i := m.bookmarkOf(selected); i >= 0 {
+		b := m.bookmarks[i]
+		m.bookmarks = slices.Delete(slices.Clone(m.bookmarks), i, i+1)
+
+		return m, tea.Batch(m.mark(), func() tea.Msg {
+			if err := store.RemoveBookmark(b); err != nil {
+				log.Printf("remove bookmark: %v\n", err)
+			}
+			return nil
+		})
+	}
+
+	return m.openPrompt(notePrompt, "note: ", "optional")
+}
+
+func (m logsModel) openPrompt(
+	prompt logsPrompt,
+	label string,
+	placeholder string,
+) (tea.Model, tea.Cmd) {
+	m.prompt = prompt
+	m.promptErr = ""
+	m.input = textinput.New()
+	m.input.Prompt = label
+	m.input.Placeholder = placeholder
+	m.input.Focus()
+
+	return m.layout(), textinput.Blink
+}
+
+func (m logsModel) closePrompt() logsModel {
+	m.prompt = noPrompt
+	return m.layout()
+}
+
+func (m logsModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, tui.Keys.ForceQuit):
+		return m, tea.Quit
+	case key.Matches(msg, tui.Keys.CancelFilter):
+		return m.closePrompt(), nil
+	case key.Matches(msg, tui.Keys.AcceptFilter) && m.prompt == notePrompt:
+		return m.closePrompt().addBookmark(m.input.Value())
+	case key.Matches(msg, tui.Keys.AcceptFilter) && m.prompt == timePrompt:
+		t, err := tui.ParseTime(m.input.Value(), time.Now(), m.source.Meta().Start)
+		if err != nil {
+			m.promptErr = err.Error()
+			return m, nil
+		}
+		return m.closePrompt().goTo(t)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m logsModel) addBookmark(note string) (tea.Model, tea.Cmd) {
+	selected, ok := m.list.Selected()
+	if !ok {
+		return m, nil
+	}
+
+	meta := m.source.Meta()
+
+	b := store.Bookmark{
+		Cluster:   meta.Cluster,
+		Namespace: meta.Namespace,
+		Pod:       meta.Pod,
+		Container: meta.Container,
+		Time:      selected.Time,
+		Line:      selected.Text,
+		Note:      note,
+		Created:   time.Now(),
+	}
+	m.bookmarks = append(slices.Clone(m.bookmarks), b)
+
+	return m, tea.Batch(m.mark(), func() tea.Msg {
+		if err := store.AddBookmark(b); err != nil {
+			log.Printf("save bookmark: %v\n", err)
+		}
+		return nil
+	})
+}
+
+// goTo selects the first line at or after t. lines from before the loaded
+// ones are fetched first.
+func (m logsModel) goTo(t time.Time) (tea.Model, tea.Cmd) {
+	first, last, timed := m.loadedTimes()
+
+	// without times there's no telling what's loaded, so everything is
+	// fetched from t
+	older := !timed || first.After(t)
+	loaded := !m.source.since.IsZero() && !m.source.since.After(t)
+	// a stream that's still going hasn't sent the lines after its last one
+	newer := m.source.lines != nil && !m.closed && (!timed || last.Before(t))
+
+	if ((older && !loaded) || newer) && m.source.reload != nil {
+		return m, func() tea.Msg { return m.source.reload(t) }
+	}
+
+	m.seek(t)
+	return m, nil
+}
+
+// loadedTimes are the times of the first and last loaded entries that have
+// one. it reports false when none do.
+func (m logsModel) loadedTimes() (time.Time, time.Time, bool) {
+	var first, last time.Time
+
+	for _, item := range m.list.Items() {
+		l, ok := item.(tui.Log)
+		if !ok || l.Time.IsZero() {
+			continue
+		}
+		if first.IsZero() {
+			first = l.Time
+		}
+		last = l.Time
+	}
+
+	return first, last, !first.IsZero()
+}
+
+func (m logsModel) seek(t time.Time) {
+	items := m.list.VisibleItems()
+
+	i := sort.Search(len(items), func(i int) bool {
+		return !items[i].Time.Before(t)
+	})
+
+	m.list.Select(min(i, max(len(items)-1, 0)))
+}
+
+// expandedView renders the lines of the selected entry, cut to the panel's
+// height
+func (m logsModel) expandedView() string {
+	// the border takes a line
+	height := m.expandedHeight() - 1
+	style := lipgloss.
+		NewStyle().
+		Width(m.size.Width).
+		Height(height).
+		PaddingLeft(4).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderTop(true).
+		BorderForeground(tui.ActiveTheme.Muted)
+
+	selected, ok := m.list.Selected()
+	if !ok {
+		return style.Render("")
+	}
+
+	lines := selected.Lines()
+
+	if len(lines) > height {
+		more := len(lines) - height + 1
+		lines = append(lines[:height-1], fmt.Sprintf("… %d more lines", more))
+	}
+
+	text := strings.ReplaceAll(strings.Join(lines, "\n"), "\t", "    ")
+
+	// long lines are cut rather than wrapped, so the panel keeps its height
+	return style.Render(lipgloss.NewStyle().MaxWidth(m.size.Width - 4).Render(text))
+}
+
+func (m logsModel) View() string {
+	if m.detail != nil {
+		return m.detail.View()
+	}
+
+	view := m.list.View()
+
+	if m.timelineHeight() > 0 {
+		if h, ok := m.histogram(); ok {
+			strip := h.Render(timelineBars, m.cursor(h))
+			view = lipgloss.NewStyle().PaddingLeft(4).Render(strip) + "\n" + view
+		}
+	}
+
+	if m.expanded {
+		view += "\n" + m.expandedView()
+	}
+
+	if m.prompt == noPrompt {
+		return view
+	}
+
+	prompt := m.input.View()
+
+	if m.promptErr != "" {
+		prompt += " " + lipgloss.
+			NewStyle().
+			Foreground(tui.ActiveTheme.Error).
+			Render(m.promptErr)
+	}
+
+	return view + "\n" + prompt
+}
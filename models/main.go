@@ -1,106 +1,503 @@
 package models
 
 import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/local"
+	"github.com/joshuasprow/log-viewer/pkg"
 	"github.com/joshuasprow/log-viewer/tui"
 )
 
 type mainModel struct {
-	msgCh chan<- tea.Msg
-	size  tea.WindowSizeMsg
-	view  tea.Model
-	data  tui.ViewData
-	err   error
+	ctx        context.Context
+	cfg        pkg.Config
+	cluster    string
+	dispatcher tui.Dispatcher
+	sources    tui.LogSources
+	// clusterless is set when only local files are viewed, and home is the
+	// view every tab starts at then
+	clusterless bool
+	home        tea.Msg
+	size        tea.WindowSizeMsg
+	queue       tea.Model
+
+	tabs      []tab
+	active    int
+	lastTabID int
+	// lastRequestID numbers the requests of all tabs, so every response
+	// finds its way back to the tab that asked for it
+	lastRequestID tui.RequestID
+
+	// renaming is set while nameInput edits the active tab's name
+	renaming  bool
+	nameInput textinput.Model
+}
+
+// inputCapturer is implemented by views that sometimes need every key,
+// e.g. while typing a filter
+type inputCapturer interface {
+	CapturingInput() bool
 }
 
-func Main(msgCh chan<- tea.Msg) mainModel {
+func capturingInput(view tea.Model) bool {
+	c, ok := view.(inputCapturer)
+	return ok && c.CapturingInput()
+}
+
+func Main(
+	ctx context.Context,
+	cfg pkg.Config,
+	kubeContext k8s.KubeContext,
+	dispatcher tui.Dispatcher,
+	sources tui.LogSources,
+) mainModel {
 	size := tea.WindowSizeMsg{Width: 80, Height: 24}
 
 	return mainModel{
-		msgCh: msgCh,
-		size:  size,
+		ctx:        ctx,
+		cfg:        cfg,
+		cluster:    kubeContext.Cluster,
+		dispatcher: dispatcher,
+		sources:    sources,
+		size:       size,
+		tabs:       []tab{newTab(0)},
 	}
 }
 
+// Local views local log files only, without a cluster to connect to. tabs
+// start at home, e.g. the file list.
+func Local(
+	ctx context.Context,
+	cfg pkg.Config,
+	dispatcher tui.Dispatcher,
+	sources tui.LogSources,
+	home tea.Msg,
+) mainModel {
+	m := Main(ctx, cfg, k8s.KubeContext{Cluster: local.Cluster}, dispatcher, sources)
+	m.clusterless = true
+	m.home = home
+	return m
+}
+
 func (m mainModel) Init() tea.Cmd {
-	return func() tea.Msg {
-		m.msgCh <- tui.NamespacesViewMsg{}
-		return nil
-	}
+	return m.openTab(0, m.cfg.Namespace)
+}
+
+// openTab starts a tab at the APIs of namespace, or at the namespace list
+// when there's none. without a cluster it starts at home.
+func (m mainModel) openTab(id int, namespace string) tea.Cmd {
+	return routeToTab(id, func() tea.Msg {
+		if m.clusterless {
+			return m.home
+		}
+		if namespace != "" {
+			return tui.ApisViewMsg{Namespace: namespace}
+		}
+		return tui.NamespacesViewMsg{}
+	})
+}
+
+// viewSize leaves room for the tab bar
+func (m mainModel) viewSize() tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{Width: m.size.Width, Height: m.size.Height - 1}
+}
+
+func (m mainModel) tabIndex(id int) int {
+	return slices.IndexFunc(m.tabs, func(t tab) bool { return t.id == id })
+}
+
+func (m mainModel) requestTab(id tui.RequestID) int {
+	return slices.IndexFunc(m.tabs, func(t tab) bool { return t.requestID == id })
+}
+
+// request asks the dispatcher to load data for the tab's next view, then
+// cancels its current request. submitting first lets an identical request
+// (e.g. reopening the same view) share the fetch that's already running.
+func (m mainModel) request(i int, msg tea.Msg) mainModel {
+	ctx, cancel := context.WithCancel(m.ctx)
+
+	m = m.track(i, msg)
+	m.dispatcher.Submit(tui.Request{ID: m.tabs[i].requestID, Ctx: ctx, Msg: msg})
+
+	m.tabs[i].cancel()
+	m.tabs[i].cancel = cancel
+
+	return m
+}
+
+// show switches the tab to a view that doesn't load anything
+func (m mainModel) show(i int, msg tea.Msg) mainModel {
+	m = m.track(i, msg)
+
+	m.tabs[i].cancel()
+	m.tabs[i].cancel = func() {}
+
+	return m
+}
+
+// stream switches the tab to a view that loads its own data until the
+// returned context is cancelled
+func (m mainModel) stream(i int, msg tea.Msg) (mainModel, context.Context) {
+	ctx, cancel := context.WithCancel(m.ctx)
+
+	m = m.track(i, msg)
+
+	m.tabs[i].cancel()
+	m.tabs[i].cancel = cancel
+
+	return m, ctx
+}
+
+func (m mainModel) track(i int, msg tea.Msg) mainModel {
+	m.lastRequestID++
+
+	t := &m.tabs[i]
+	t.push(msg)
+	t.retries = 0
+	t.requestID = m.lastRequestID
+
+	return m
 }
 
 func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if m.err != nil {
-		m.view = Error(m.size, m.err, m.msgCh)
-		m.err = nil
+	if msg, ok := msg.(tea.KeyMsg); ok && m.queue != nil {
+		switch {
+		case key.Matches(msg, tui.Keys.ForceQuit):
+			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.Debug, tui.Keys.Back):
+			m.queue = nil
+		}
 		return m, nil
 	}
 
 	switch msg := msg.(type) {
-	case error:
-		m.err = msg
-		return m, nil
+	case queueTickMsg:
+		if m.queue == nil {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.queue, cmd = m.queue.Update(msg)
+		return m, cmd
+	case tabMsg:
+		i := m.tabIndex(msg.id)
+		if i < 0 {
+			return m, nil
+		}
+		return m.updateTab(i, msg.msg)
+	case tui.ErrorMsg:
+		i := m.requestTab(msg.RequestID)
+		if i < 0 {
+			log.Printf("drop stale error: %v\n", msg)
+			return m, nil
+		}
+		return m.updateTab(i, msg)
+	case tui.ItemsMsg:
+		i := m.requestTab(msg.RequestID)
+		if i < 0 {
+			log.Printf("drop stale items for request %d\n", msg.RequestID)
+			return m, nil
+		}
+		return m.updateTab(i, msg)
 	case tea.WindowSizeMsg:
 		m.size.Width = msg.Width
 		m.size.Height = msg.Height - 1 // todo: fixes list title disappearing
+		if m.queue != nil {
+			m.queue, _ = m.queue.Update(m.size)
+		}
+
+		cmds := []tea.Cmd{}
+
+		for i := range m.tabs {
+			var cmd tea.Cmd
+			m, cmd = m.updateTab(i, m.viewSize())
+			cmds = append(cmds, cmd)
+		}
+
+		return m, tea.Batch(cmds...)
+	case tea.KeyMsg:
+		if m.renaming {
+			return m.updateName(msg)
+		}
+		if capturingInput(m.tabs[m.active].view) {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, tui.Keys.Debug):
+			m.queue = Queue(m.size, m.dispatcher)
+			return m, m.queue.Init()
+		case key.Matches(msg, tui.Keys.TimeGutter):
+			tui.TimeGutter = tui.TimeGutter.Next()
+			return m, nil
+		case key.Matches(msg, tui.Keys.NewTab):
+			m.lastTabID++
+			m.tabs = append(m.tabs, newTab(m.lastTabID))
+			namespace := m.tabs[m.active].data.Namespace
+			m = m.focusTab(len(m.tabs) - 1)
+			return m, m.openTab(m.lastTabID, namespace)
+		case key.Matches(msg, tui.Keys.CloseTab):
+			if len(m.tabs) == 1 {
+				return m, nil
+			}
+			m.tabs[m.active].cancel()
+			m.tabs = slices.Delete(slices.Clone(m.tabs), m.active, m.active+1)
+			return m.focusTab(min(m.active, len(m.tabs)-1)), nil
+		case key.Matches(msg, tui.Keys.NextTab):
+			return m.focusTab((m.active + 1) % len(m.tabs)), nil
+		case key.Matches(msg, tui.Keys.PrevTab):
+			return m.focusTab((m.active + len(m.tabs) - 1) % len(m.tabs)), nil
+		case key.Matches(msg, tui.Keys.RenameTab):
+			m.renaming = true
+			m.nameInput = textinput.New()
+			m.nameInput.Prompt = ""
+			m.nameInput.Placeholder = m.tabs[m.active].title()
+			m.nameInput.CharLimit = maxTabTitle
+			m.nameInput.SetValue(m.tabs[m.active].name)
+			m.nameInput.Focus()
+			return m, textinput.Blink
+		}
+	}
+
+	if m.renaming {
+		var inputCmd, cmd tea.Cmd
+		m.nameInput, inputCmd = m.nameInput.Update(msg)
+		m, cmd = m.updateTab(m.active, msg)
+		return m, tea.Batch(inputCmd, cmd)
+	}
+
+	return m.updateTab(m.active, msg)
+}
+
+func (m mainModel) focusTab(i int) mainModel {
+	m.active = i
+	m.tabs[i].unread = 0
+	return m
+}
+
+func (m mainModel) updateName(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, tui.Keys.ForceQuit):
+		return m, tea.Quit
+	case key.Matches(msg, tui.Keys.CancelFilter):
+		m.renaming = false
+		return m, nil
+	case key.Matches(msg, tui.Keys.AcceptFilter):
+		m.renaming = false
+		m.tabs[m.active].name = strings.TrimSpace(m.nameInput.Value())
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+// updateTab handles a message for tab i. commands of its view report back
+// to the same tab.
+func (m mainModel) updateTab(i int, msg tea.Msg) (mainModel, tea.Cmd) {
+	if i != m.active {
+		m.tabs[i].unread += newLines(msg)
+	}
+
+	m, cmd := m.updateView(i, msg)
+
+	return m, routeToTab(m.tabs[i].id, cmd)
+}
+
+func (m mainModel) updateView(i int, msg tea.Msg) (mainModel, tea.Cmd) {
+	t := &m.tabs[i]
+	size := m.viewSize()
+
+	switch msg := msg.(type) {
+	case tui.ErrorMsg:
+		// views that load their own data fail without a request of their
+		// own, e.g. a log view's stream
+		if msg.RequestID == 0 {
+			msg.RequestID = t.requestID
+		}
+		t.view = Error(size, msg, t.previous(), t.retries)
+		return m, t.view.Init()
+	case retryMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateView(i, msg.msg)
+		m.tabs[i].retries = msg.attempt
+		return m, cmd
+	case backMsg:
+		if prev := t.previous(); prev != nil {
+			return m.updateView(i, prev)
+		}
+		if msg.parent != nil {
+			return m.updateView(i, msg.parent)
+		}
+		return m, nil
+	case tui.ItemsMsg:
+		if t.view == nil {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		t.view, cmd = t.view.Update(msg.Items)
+		return m, cmd
 	case tui.NamespacesViewMsg:
-		m.view = Namespaces(m.size, m.msgCh)
-		return m, m.view.Init()
+		m = m.request(i, msg)
+		t.view = Namespaces(size)
+		return m, t.view.Init()
+	case tui.BookmarksViewMsg:
+		m = m.request(i, msg)
+		t.data.Namespace = msg.Namespace
+		t.view = Bookmarks(size, m.cluster, msg)
+		return m, t.view.Init()
+	case tui.NamespacePromptViewMsg:
+		m = m.show(i, msg)
+		t.view = NamespacePrompt(size, m.cfg.Context)
+		return m, t.view.Init()
 	case tui.ApisViewMsg:
-		m.data.Namespace = msg.Namespace
-		m.view = Apis(m.size, m.data.Namespace, m.msgCh)
-		return m, m.view.Init()
+		m = m.request(i, msg)
+		t.data.Namespace = msg.Namespace
+		t.view = Apis(size, t.data.Namespace)
+		return m, t.view.Init()
 	case tui.ContainersViewMsg:
-		m.data.Namespace = msg.Namespace
-		m.data.Api = msg.Api
-		m.view = Containers(m.size, m.data.Namespace, m.msgCh)
-		return m, m.view.Init()
+		m = m.request(i, msg)
+		t.data.Namespace = msg.Namespace
+		t.data.Api = msg.Api
+		t.data.LabelSelector = msg.LabelSelector
+		t.data.FieldSelector = msg.FieldSelector
+		t.view = Containers(size, msg)
+		return m, t.view.Init()
+	case tui.SelectorPromptViewMsg:
+		m = m.show(i, msg)
+		t.view = SelectorPrompt(size, msg.Containers)
+		return m, t.view.Init()
 	case tui.ContainerLogsViewMsg:
-		m.data.Container = msg.Container
-		m.view = ContainerLogs(m.size, m.data.Container, m.msgCh)
-		return m, m.view.Init()
+		var ctx context.Context
+		m, ctx = m.stream(i, msg)
+		t.data.Container = msg.Container
+		t.view = ContainerLogs(
+			ctx,
+			size,
+			tui.ContainersViewMsg{
+				Namespace:     t.data.Namespace,
+				Api:           t.data.Api,
+				LabelSelector: t.data.LabelSelector,
+				FieldSelector: t.data.FieldSelector,
+			},
+			m.sources.Pod(t.data.Container, time.Time{}, msg.SinceTime),
+			msg.SinceTime,
+		)
+		return m, t.view.Init()
+	case tui.SplitViewMsg:
+		var ctx context.Context
+		m, ctx = m.stream(i, msg)
+		t.view = Split(
+			ctx,
+			size,
+			msg,
+			m.sources.Pod(msg.Left, time.Time{}, time.Time{}),
+			m.sources.Pod(msg.Right, time.Time{}, time.Time{}),
+		)
+		return m, t.view.Init()
+	case tui.FilesViewMsg:
+		m = m.request(i, msg)
+		t.data.Namespace = msg.Namespace
+		t.view = Files(size, t.data.Namespace, m.clusterless)
+		return m, t.view.Init()
+	case tui.FileLogsViewMsg:
+		var ctx context.Context
+		m, ctx = m.stream(i, msg)
+		t.view = FileLogs(ctx, size, msg, m.sources.File(msg.Path))
+		return m, t.view.Init()
+	case tui.SessionViewMsg:
+		m = m.request(i, msg)
+		t.view = Session(size)
+		return m, t.view.Init()
+	case tui.ReplayLogsViewMsg:
+		var ctx context.Context
+		m, ctx = m.stream(i, msg)
+		t.view = ReplayLogs(ctx, size, msg, m.sources.Replay(msg.Stream, msg.SinceTime))
+		return m, t.view.Init()
 	case tui.CronJobsViewMsg:
-		m.data.Namespace = msg.Namespace
-		m.data.Api = msg.Api
-		m.view = CronJobs(m.size, m.data.Namespace, m.msgCh)
-		return m, m.view.Init()
+		m = m.request(i, msg)
+		t.data.Namespace = msg.Namespace
+		t.data.Api = msg.Api
+		t.view = CronJobs(size, t.data.Namespace)
+		return m, t.view.Init()
+	case tui.PermissionsViewMsg:
+		m = m.request(i, msg)
+		t.data.Namespace = msg.Namespace
+		t.view = Permissions(size, t.data.Namespace)
+		return m, t.view.Init()
 	case tui.CronJobJobsViewMsg:
-		m.data.CronJob = msg.CronJob
-		m.view = CronJobJobs(m.size, m.data.CronJob, m.msgCh)
-		return m, m.view.Init()
+		m = m.request(i, msg)
+		t.data.CronJob = msg.CronJob
+		t.view = CronJobJobs(size, t.data.Namespace, t.data.CronJob, msg.Compare)
+		return m, t.view.Init()
+	case tui.JobDiffViewMsg:
+		m = m.request(i, msg)
+		t.data.CronJob = msg.CronJob
+		t.view = JobDiff(size, msg)
+		return m, t.view.Init()
 	case tui.CronJobContainersViewMsg:
-		m.data.CronJobJob = msg.Job
-		m.view = CronJobContainers(
-			m.size,
-			m.data.CronJob,
-			m.data.CronJobJob,
-			m.msgCh,
+		m = m.request(i, msg)
+		t.data.CronJobJob = msg.Job
+		t.data.CronJobArchived = msg.Archived
+		t.view = CronJobContainers(
+			size,
+			t.data.CronJob,
+			t.data.CronJobJob,
+			t.data.CronJobArchived,
 		)
-		return m, m.view.Init()
+		return m, t.view.Init()
 	case tui.CronJobLogsViewMsg:
-		m.data.CronJobContainer = msg.Container
-		m.view = CronJobLogs(
-			m.size,
-			m.data.CronJob,
-			m.data.CronJobJob,
-			m.data.CronJobContainer,
-			m.msgCh,
+		var ctx context.Context
+		m, ctx = m.stream(i, msg)
+		t.data.CronJobContainer = msg.Container
+
+		start := t.data.CronJobJob.StartTime
+
+		source := m.sources.Pod(t.data.CronJobContainer, start, msg.SinceTime)
+		if msg.Archived {
+			source = m.sources.Archived(t.data.CronJobContainer, start, msg.SinceTime)
+		}
+
+		t.view = CronJobLogs(
+			ctx,
+			size,
+			t.data.CronJob,
+			t.data.CronJobJob,
+			msg.Archived,
+			source,
+			msg.SinceTime,
 		)
+		return m, t.view.Init()
 	}
 
-	if m.view == nil {
+	if t.view == nil {
 		return m, nil
 	}
 
 	var cmd tea.Cmd
-	m.view, cmd = m.view.Update(msg)
+	t.view, cmd = t.view.Update(msg)
 	return m, cmd
 }
 
 func (m mainModel) View() string {
-	if m.view == nil {
-		return spinner.New().View()
+	if m.queue != nil {
+		return m.queue.View()
 	}
-	return m.view.View()
+
+	view := spinner.New().View()
+
+	if t := m.tabs[m.active]; t.view != nil {
+		view = t.view.View()
+	}
+
+	return m.tabBar() + "\n" + view
 }
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/store"
+	"github.com/joshuasprow/log-viewer/tui"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type namespacePromptModel struct {
+	size        tea.WindowSizeMsg
+	kubeContext string
+	input       textinput.Model
+	err         string
+}
+
+func NamespacePrompt(size tea.WindowSizeMsg, kubeContext string) tea.Model {
+	input := textinput.New()
+	input.Placeholder = "namespace"
+	input.Prompt = "> "
+	input.CharLimit = validation.DNS1123LabelMaxLength
+	input.Focus()
+
+	return namespacePromptModel{
+		size:        size,
+		kubeContext: kubeContext,
+		input:       input,
+	}
+}
+
+func (m namespacePromptModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m namespacePromptModel) CapturingInput() bool {
+	return true
+}
+
+func (m namespacePromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, tui.Keys.ForceQuit):
+			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.CancelFilter):
+			return m, func() tea.Msg { return tui.NamespacesViewMsg{} }
+		case key.Matches(msg, tui.Keys.AcceptFilter):
+			namespace := strings.TrimSpace(m.input.Value())
+
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				m.err = strings.Join(errs, "; ")
+				return m, nil
+			}
+
+			return m, func() tea.Msg {
+				if err := store.NamespaceHistory.Add(m.kubeContext, namespace); err != nil {
+					log.Printf("remember namespace: %v\n", err)
+				}
+				return tui.ApisViewMsg{Namespace: namespace}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m namespacePromptModel) View() string {
+	style := lipgloss.NewStyle().PaddingLeft(4)
+	muted := lipgloss.NewStyle().Foreground(tui.ActiveTheme.Muted)
+
+	lines := []string{
+		tui.RenderTitle("enter a namespace"),
+		"",
+		m.input.View(),
+		"",
+	}
+
+	if m.err != "" {
+		lines = append(lines, lipgloss.
+			NewStyle().
+			Width(m.size.Width-4).
+			Foreground(tui.ActiveTheme.Error).
+			Render(m.err))
+	}
+
+	lines = append(lines, muted.Render(
+		tui.Keys.AcceptFilter.Help().Key+" open • "+
+			tui.Keys.CancelFilter.Help().Key+" cancel",
+	))
+
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -1,20 +1,27 @@
 package models
 
 import (
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joshuasprow/log-viewer/models/defaults"
 	"github.com/joshuasprow/log-viewer/tui"
 )
 
-func Namespaces(size tea.WindowSizeMsg, msgCh chan<- tea.Msg) tea.Model {
-	options := defaults.ListModelOptions[tui.Namespace]{
+func Namespaces(size tea.WindowSizeMsg) tea.Model {
+	options := defaults.ListModelOptions[list.Item]{
 		Title: tui.RenderTitle("select a namespace"),
-		OnEnter: func(selected tui.Namespace, msgCh chan<- tea.Msg) {
-			msgCh <- tui.ApisViewMsg{
-				Namespace: string(selected),
+		OnEnter: func(selected list.Item) tea.Msg {
+			switch selected := selected.(type) {
+			case tui.Namespace:
+				return tui.ApisViewMsg{
+					Namespace: selected.Name,
+				}
+			case tui.NamespacePrompt:
+				return tui.NamespacePromptViewMsg{}
 			}
+			return nil
 		},
 	}
 
-	return defaults.NewListModel(size, options, msgCh)
+	return defaults.NewListModel(size, options)
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+func Permissions(
+	size tea.WindowSizeMsg,
+	namespace string,
+) tea.Model {
+	options := defaults.ListModelOptions[tui.PermissionCheck]{
+		ShowDescription: true,
+		Title:           tui.RenderTitle(tui.NamespaceTitle(namespace), "can i?"),
+		OnEsc: func() tea.Msg {
+			return tui.ApisViewMsg{
+				Namespace: namespace,
+			}
+		},
+	}
+
+	return defaults.NewListModel(size, options)
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+type queueModel struct {
+	size       tea.WindowSizeMsg
+	dispatcher tui.Dispatcher
+	stats      tui.QueueStats
+}
+
+type queueTickMsg struct{}
+
+func Queue(size tea.WindowSizeMsg, dispatcher tui.Dispatcher) tea.Model {
+	return queueModel{
+		size:       size,
+		dispatcher: dispatcher,
+		stats:      dispatcher.Stats(),
+	}
+}
+
+func queueTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return queueTickMsg{}
+	})
+}
+
+func (m queueModel) Init() tea.Cmd {
+	return queueTick()
+}
+
+func (m queueModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+	case queueTickMsg:
+		m.stats = m.dispatcher.Stats()
+		return m, queueTick()
+	}
+
+	return m, nil
+}
+
+func (m queueModel) renderEntry(e tui.QueueEntry, now time.Time) string {
+	ids := make([]string, len(e.RequestIDs))
+	for i, id := range e.RequestIDs {
+		ids[i] = fmt.Sprintf("#%d", id)
+	}
+
+	var status string
+
+	switch {
+	case e.Finished.IsZero() && e.Started.IsZero():
+		status = "queued " + now.Sub(e.Queued).Round(time.Millisecond).String()
+	case e.Finished.IsZero():
+		status = "running " + now.Sub(e.Started).Round(time.Millisecond).String()
+	case e.Cancelled:
+		status = "cancelled"
+	case e.Err != nil:
+		status = "failed " + e.Finished.Sub(e.Queued).Round(time.Millisecond).String()
+	default:
+		status = "done " + e.Finished.Sub(e.Queued).Round(time.Millisecond).String()
+	}
+
+	line := fmt.Sprintf(
+		"%-10s %-18s %T%+v",
+		strings.Join(ids, ","),
+		status,
+		e.Msg,
+		e.Msg,
+	)
+
+	if w := m.size.Width - 4; w > 0 && len(line) > w {
+		line = line[:w]
+	}
+
+	return line
+}
+
+func (m queueModel) View() string {
+	now := time.Now()
+	style := lipgloss.NewStyle().PaddingLeft(4)
+	muted := lipgloss.NewStyle().Foreground(tui.ActiveTheme.Muted)
+
+	lines := []string{
+		tui.RenderTitle("debug", "request queue"),
+		"",
+		fmt.Sprintf(
+			"workers=%d running=%d queued=%d completed=%d coalesced=%d cancelled=%d",
+			m.stats.Workers,
+			len(m.stats.Running),
+			len(m.stats.Queued),
+			m.stats.Completed,
+			m.stats.Coalesced,
+			m.stats.Cancelled,
+		),
+	}
+
+	section := func(title string, entries []tui.QueueEntry) {
+		lines = append(lines, "", title)
+
+		if len(entries) == 0 {
+			lines = append(lines, muted.Render("none"))
+		}
+
+		for _, e := range entries {
+			lines = append(lines, m.renderEntry(e, now))
+		}
+	}
+
+	section("running", m.stats.Running)
+	section("queued", m.stats.Queued)
+	section("recent", m.stats.Recent)
+
+	lines = append(
+		lines,
+		"",
+		muted.Render(fmt.Sprintf(
+			"%s/%s close",
+			tui.Keys.Debug.Help().Key,
+			tui.Keys.Back.Help().Key,
+		)),
+	)
+
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/store"
+	"github.com/joshuasprow/log-viewer/tui"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	labelSelectorKey = "label"
+	fieldSelectorKey = "field"
+)
+
+type selectorHistoryMsg struct {
+	label []string
+	field []string
+}
+
+// selectorInput is one selector field with its history. position -1 is
+// the value being typed, which is kept in draft while browsing history.
+type selectorInput struct {
+	input    textinput.Model
+	history  []string
+	position int
+	draft    string
+}
+
+func (s *selectorInput) browse(step int) {
+	next := s.position + step
+
+	if next < -1 || next >= len(s.history) {
+		return
+	}
+
+	if s.position == -1 {
+		s.draft = s.input.Value()
+	}
+
+	s.position = next
+
+	if next == -1 {
+		s.input.SetValue(s.draft)
+	} else {
+		s.input.SetValue(s.history[next])
+	}
+
+	s.input.CursorEnd()
+}
+
+type selectorPromptModel struct {
+	size       tea.WindowSizeMsg
+	containers tui.ContainersViewMsg
+	inputs     [2]selectorInput
+	focused    int
+	err        string
+}
+
+func SelectorPrompt(
+	size tea.WindowSizeMsg,
+	containers tui.ContainersViewMsg,
+) tea.Model {
+	newInput := func(placeholder string, value string) selectorInput {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.Prompt = "> "
+		input.SetValue(value)
+		return selectorInput{input: input, position: -1}
+	}
+
+	m := selectorPromptModel{
+		size:       size,
+		containers: containers,
+		inputs: [2]selectorInput{
+			newInput("app=web,tier!=cache", containers.LabelSelector),
+			newInput("status.phase=Running", containers.FieldSelector),
+		},
+	}
+	m.inputs[0].input.Focus()
+
+	return m
+}
+
+func (m selectorPromptModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, func() tea.Msg {
+		label, err := store.SelectorHistory.Load(labelSelectorKey)
+		if err != nil {
+			log.Printf("load label selector history: %v\n", err)
+		}
+		field, err := store.SelectorHistory.Load(fieldSelectorKey)
+		if err != nil {
+			log.Printf("load field selector history: %v\n", err)
+		}
+		return selectorHistoryMsg{label: label, field: field}
+	})
+}
+
+func (m selectorPromptModel) CapturingInput() bool {
+	return true
+}
+
+func (m selectorPromptModel) focus(i int) selectorPromptModel {
+	m.inputs[m.focused].input.Blur()
+	m.focused = (i + len(m.inputs)) % len(m.inputs)
+	m.inputs[m.focused].input.Focus()
+	return m
+}
+
+func (m selectorPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+	case selectorHistoryMsg:
+		m.inputs[0].history = msg.label
+		m.inputs[1].history = msg.field
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, tui.Keys.ForceQuit):
+			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.CancelFilter):
+			return m, func() tea.Msg { return m.containers }
+		case key.Matches(msg, tui.Keys.NextField):
+			return m.focus(m.focused + 1), nil
+		case key.Matches(msg, tui.Keys.PrevField):
+			return m.focus(m.focused - 1), nil
+		case key.Matches(msg, tui.Keys.HistoryPrev):
+			m.inputs[m.focused].browse(1)
+			return m, nil
+		case key.Matches(msg, tui.Keys.HistoryNext):
+			m.inputs[m.focused].browse(-1)
+			return m, nil
+		case key.Matches(msg, tui.Keys.AcceptFilter):
+			return m.accept()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focused].input, cmd = m.inputs[m.focused].input.Update(msg)
+	return m, cmd
+}
+
+func (m selectorPromptModel) accept() (tea.Model, tea.Cmd) {
+	label := strings.TrimSpace(m.inputs[0].input.Value())
+	field := strings.TrimSpace(m.inputs[1].input.Value())
+
+	if _, err := labels.Parse(label); err != nil {
+		m.err = "label selector: " + err.Error()
+		return m.focus(0), nil
+	}
+	if _, err := fields.ParseSelector(field); err != nil {
+		m.err = "field selector: " + err.Error()
+		return m.focus(1), nil
+	}
+
+	containers := m.containers
+	containers.LabelSelector = label
+	containers.FieldSelector = field
+
+	return m, func() tea.Msg {
+		for key, value := range map[string]string{
+			labelSelectorKey: label,
+			fieldSelectorKey: field,
+		} {
+			if value == "" {
+				continue
+			}
+			if err := store.SelectorHistory.Add(key, value); err != nil {
+				log.Printf("remember %s selector: %v\n", key, err)
+			}
+		}
+		return containers
+	}
+}
+
+func (m selectorPromptModel) View() string {
+	style := lipgloss.NewStyle().PaddingLeft(4)
+	muted := lipgloss.NewStyle().Foreground(tui.ActiveTheme.Muted)
+
+	lines := []string{
+		tui.RenderTitle(tui.NamespaceTitle(m.containers.Namespace), "selectors"),
+		"",
+		muted.Render("labels"),
+		m.inputs[0].input.View(),
+		"",
+		muted.Render("fields"),
+		m.inputs[1].input.View(),
+		"",
+	}
+
+	if m.err != "" {
+		lines = append(lines, lipgloss.
+			NewStyle().
+			Width(m.size.Width-4).
+			Foreground(tui.ActiveTheme.Error).
+			Render(m.err))
+	}
+
+	lines = append(lines, muted.Render(strings.Join([]string{
+		tui.Keys.AcceptFilter.Help().Key + " apply",
+		tui.Keys.NextField.Help().Key + " next field",
+		tui.Keys.HistoryPrev.Help().Key + "/" +
+			tui.Keys.HistoryNext.Help().Key + " history",
+		tui.Keys.CancelFilter.Help().Key + " cancel",
+	}, " • ")))
+
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+// Session lists the streams of the session being replayed
+func Session(size tea.WindowSizeMsg) tea.Model {
+	options := defaults.ListModelOptions[tui.Stream]{
+		ShowDescription: true,
+		Title:           tui.RenderTitle("recorded session", "select a stream"),
+		OnEnter: func(selected tui.Stream) tea.Msg {
+			return tui.ReplayLogsViewMsg{Stream: selected.ID}
+		},
+	}
+
+	return defaults.NewListModel(size, options)
+}
+
+func ReplayLogs(
+	ctx context.Context,
+	size tea.WindowSizeMsg,
+	msg tui.ReplayLogsViewMsg,
+	source pkg.LogSource,
+) tea.Model {
+	options := defaults.ListModelOptions[tui.Log]{
+		OnEsc: func() tea.Msg {
+			return tui.SessionViewMsg{}
+		},
+	}
+
+	return newLogsModel(size, logsSource{
+		LogSource: source,
+		since:     msg.SinceTime,
+		reload: func(since time.Time) tea.Msg {
+			return tui.ReplayLogsViewMsg{
+				Stream:    msg.Stream,
+				SinceTime: since,
+			}
+		},
+		lines: follow(ctx, source),
+		view:  msg,
+	}, options)
+}
@@ -0,0 +1,382 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/models/defaults"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+// maxPaneBatch caps how many buffered lines a pane takes per update
+const maxPaneBatch = 500
+
+type splitLayout int
+
+const (
+	sideBySide splitLayout = iota
+	stacked
+)
+
+// linesMsg carries lines read from the stream ch. it's routed by ch,
+// so lines from a view the user already closed are dropped.
+type linesMsg struct {
+	ch      <-chan pkg.Result[string]
+	results []pkg.Result[string]
+	closed  bool
+}
+
+func waitForLines(ch <-chan pkg.Result[string]) tea.Cmd {
+	return func() tea.Msg {
+		r, ok := <-ch
+		if !ok {
+			return linesMsg{ch: ch, closed: true}
+		}
+
+		msg := linesMsg{ch: ch, results: []pkg.Result[string]{r}}
+
+		// take whatever else is buffered, so bursts render once
+		for len(msg.results) < maxPaneBatch {
+			select {
+			case r, ok := <-ch:
+				if !ok {
+					msg.closed = true
+					return msg
+				}
+				msg.results = append(msg.results, r)
+			default:
+				return msg
+			}
+		}
+
+		return msg
+	}
+}
+
+type logPane struct {
+	meta   pkg.LogMeta
+	ch     <-chan pkg.Result[string]
+	list   defaults.ListModel[tui.Log]
+	closed bool
+	// last is the latest entry, which later lines may continue
+	last tui.Log
+}
+
+func (p logPane) title() string {
+	status := "following"
+
+	if p.closed {
+		status = "ended"
+	}
+
+	return tui.RenderTitle(append(metaTitle(p.meta), status)...)
+}
+
+// selectedTime is the time of the selected line, or of the closest line
+// above it that has one
+func (p logPane) selectedTime() (time.Time, bool) {
+	items := p.list.VisibleItems()
+
+	for i := min(p.list.Index(), len(items)-1); i >= 0; i-- {
+		if !items[i].Time.IsZero() {
+			return items[i].Time, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// seek selects the last line logged at or before t
+func (p logPane) seek(t time.Time) {
+	items := p.list.VisibleItems()
+
+	i := sort.Search(len(items), func(i int) bool {
+		return items[i].Time.After(t)
+	})
+
+	p.list.Select(max(i-1, 0))
+}
+
+type splitModel struct {
+	size    tea.WindowSizeMsg
+	msg     tui.SplitViewMsg
+	panes   [2]logPane
+	focused int
+	layout  splitLayout
+	sync    bool
+}
+
+func Split(
+	ctx context.Context,
+	size tea.WindowSizeMsg,
+	msg tui.SplitViewMsg,
+	left pkg.LogSource,
+	right pkg.LogSource,
+) tea.Model {
+	back := metaContainer(left.Meta())
+
+	newPane := func(source pkg.LogSource) logPane {
+		options := defaults.ListModelOptions[tui.Log]{
+			HideHelp: true,
+			OnEsc: func() tea.Msg {
+				return tui.ContainerLogsViewMsg{Container: back}
+			},
+		}
+
+		return logPane{
+			meta: source.Meta(),
+			ch:   follow(ctx, source),
+			list: defaults.NewListModel(size, options),
+		}
+	}
+
+	m := splitModel{
+		size:  size,
+		msg:   msg,
+		panes: [2]logPane{newPane(left), newPane(right)},
+	}
+
+	return m.resize()
+}
+
+func (m splitModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.panes[0].list.Init(),
+		m.panes[1].list.Init(),
+		waitForLines(m.panes[0].ch),
+		waitForLines(m.panes[1].ch),
+	)
+}
+
+func (m splitModel) CapturingInput() bool {
+	return m.panes[m.focused].list.CapturingInput()
+}
+
+// paneSizes returns the inner size of both panes, leaving room for their
+// borders and the help line
+func (m splitModel) paneSizes() [2]tea.WindowSizeMsg {
+	width := m.size.Width
+	height := m.size.Height - 1
+
+	if m.layout == sideBySide {
+		return [2]tea.WindowSizeMsg{
+			{Width: width/2 - 2, Height: height - 2},
+			{Width: width - width/2 - 2, Height: height - 2},
+		}
+	}
+
+	return [2]tea.WindowSizeMsg{
+		{Width: width - 2, Height: height/2 - 2},
+		{Width: width - 2, Height: height - height/2 - 2},
+	}
+}
+
+func (m splitModel) resize() splitModel {
+	for i, size := range m.paneSizes() {
+		m.updatePane(i, size)
+		m.panes[i].list.SetTitle(m.panes[i].title())
+	}
+	return m
+}
+
+// syncScroll moves the other pane to the time of the focused pane's line
+func (m splitModel) syncScroll() {
+	if !m.sync {
+		return
+	}
+	if t, ok := m.panes[m.focused].selectedTime(); ok {
+		m.panes[1-m.focused].seek(t)
+	}
+}
+
+func (m splitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+		return m.resize(), nil
+	case linesMsg:
+		return m.appendLines(msg)
+	case tea.KeyMsg:
+		if m.CapturingInput() {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, tui.Keys.FocusPane):
+			m.focused = 1 - m.focused
+			return m, nil
+		case key.Matches(msg, tui.Keys.SplitLayout):
+			m.layout = 1 - m.layout
+			return m.resize(), nil
+		case key.Matches(msg, tui.Keys.SyncScroll):
+			m.sync = !m.sync
+			m.syncScroll()
+			return m, nil
+		}
+	case paneMsg:
+		return m, m.updatePane(msg.pane, msg.msg)
+	}
+
+	if _, ok := msg.(tea.KeyMsg); ok {
+		cmd := m.updatePane(m.focused, msg)
+		m.syncScroll()
+		return m, cmd
+	}
+
+	// everything else, e.g. spinner ticks, carries its own id
+	return m, tea.Batch(m.updatePane(0, msg), m.updatePane(1, msg))
+}
+
+// paneMsg is a message for a single pane
+type paneMsg struct {
+	pane int
+	msg  tea.Msg
+}
+
+func (m *splitModel) updatePane(i int, msg tea.Msg) tea.Cmd {
+	lm, cmd := m.panes[i].list.Update(msg)
+	m.panes[i].list = lm.(defaults.ListModel[tui.Log])
+	return routeTo(i, cmd)
+}
+
+// routeTo tags the filter results of a pane's list, the only list message
+// that doesn't say which list it's for
+func routeTo(pane int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for i := range msg {
+				msg[i] = routeTo(pane, msg[i])
+			}
+			return msg
+		case list.FilterMatchesMsg:
+			return paneMsg{pane: pane, msg: msg}
+		default:
+			return msg
+		}
+	}
+}
+
+func (m splitModel) appendLines(msg linesMsg) (tea.Model, tea.Cmd) {
+	for i := range m.panes {
+		pane := &m.panes[i]
+
+		if pane.ch != msg.ch {
+			continue
+		}
+
+		lines := []string{}
+
+		for _, r := range msg.results {
+			if r.Err != nil {
+				return m, sourceFailed(m.msg, r.Err)
+			}
+			lines = append(lines, r.V)
+		}
+
+		var cmd tea.Cmd
+		pane.last, cmd = appendLines(pane.list, pane.last, lines)
+
+		cmds := []tea.Cmd{routeTo(i, cmd)}
+
+		if msg.closed {
+			pane.closed = true
+			pane.list.StopSpinner()
+		} else {
+			cmds = append(cmds, waitForLines(pane.ch))
+		}
+
+		pane.list.SetTitle(pane.title())
+
+		if i != m.focused {
+			m.syncScroll()
+		}
+
+		return m, tea.Batch(cmds...)
+	}
+
+	return m, nil
+}
+
+// appendLines groups lines into entries at the end of l. last is the entry
+// l ends with, which lines may continue. the new last entry is returned.
+func appendLines(
+	l defaults.ListModel[tui.Log],
+	last tui.Log,
+	lines []string,
+) (tui.Log, tea.Cmd) {
+	items, continued := tui.ParseLogs(last, lines)
+	if len(items) > 0 {
+		last = items[len(items)-1].(tui.Log)
+	}
+
+	cmds := []tea.Cmd{}
+
+	if continued {
+		cmds = append(cmds, l.SetLast(items[0]))
+		items = items[1:]
+	}
+
+	return last, tea.Batch(append(cmds, l.Append(items...))...)
+}
+
+func (m splitModel) View() string {
+	sizes := m.paneSizes()
+	views := make([]string, len(m.panes))
+
+	for i, pane := range m.panes {
+		color := tui.ActiveTheme.Muted
+		if i == m.focused {
+			color = tui.ActiveTheme.TitleCurrent
+		}
+
+		views[i] = lipgloss.
+			NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Width(sizes[i].Width).
+			Height(sizes[i].Height).
+			MaxHeight(sizes[i].Height + 2).
+			Render(lipgloss.
+				NewStyle().
+				MaxWidth(sizes[i].Width).
+				Render(pane.list.View()))
+	}
+
+	var panes string
+
+	if m.layout == sideBySide {
+		panes = lipgloss.JoinHorizontal(lipgloss.Top, views...)
+	} else {
+		panes = lipgloss.JoinVertical(lipgloss.Left, views...)
+	}
+
+	sync := "off"
+	if m.sync {
+		sync = "on"
+	}
+
+	help := lipgloss.NewStyle().Foreground(tui.ActiveTheme.Muted).Render(
+		strings.Join([]string{
+			tui.Keys.FocusPane.Help().Key + " switch pane",
+			tui.Keys.SplitLayout.Help().Key + " layout",
+			tui.Keys.SyncScroll.Help().Key + " sync scroll (" + sync + ")",
+			tui.Keys.TimeGutter.Help().Key + " timestamps (" + tui.TimeGutter.String() + ")",
+			tui.Keys.Filter.Help().Key + " filter",
+			tui.Keys.Back.Help().Key + " close",
+		}, " • "),
+	)
+
+	return panes + "\n" + help
+}
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/local"
+	"github.com/joshuasprow/log-viewer/tui"
+)
+
+// maxTabTitle keeps a few tabs visible side by side
+const maxTabTitle = 24
+
+// tab is a session of its own: a view, the data it was opened with and
+// the request that's loading it
+type tab struct {
+	id   int
+	name string
+	view tea.Model
+	data tui.ViewData
+
+	// requestID tags the data request of the tab's current view. responses
+	// for any other request belong to a view the user already left.
+	requestID tui.RequestID
+	cancel    context.CancelFunc
+
+	// stack holds the msgs that opened the views the tab went through to
+	// get to the current one, which is last
+	stack []tea.Msg
+	// retries counts automatic retries of the current view after transient
+	// errors
+	retries int
+
+	// unread counts log lines that arrived while the tab was in the
+	// background
+	unread int
+}
+
+func newTab(id int) tab {
+	return tab{id: id, cancel: func() {}}
+}
+
+// current is the msg that opened the tab's view
+func (t tab) current() tea.Msg {
+	if len(t.stack) == 0 {
+		return nil
+	}
+	return t.stack[len(t.stack)-1]
+}
+
+// previous is the msg of the view before the current one, if any
+func (t tab) previous() tea.Msg {
+	if len(t.stack) < 2 {
+		return nil
+	}
+	return t.stack[len(t.stack)-2]
+}
+
+// push makes msg the current view's. a view of a kind that's on the stack
+// already replaces it and the views above it, so the stack is the path
+// from the tab's first view to the current one without detours.
+func (t *tab) push(msg tea.Msg) {
+	for i, m := range t.stack {
+		if reflect.TypeOf(m) == reflect.TypeOf(msg) {
+			t.stack = append(t.stack[:i:i], msg)
+			return
+		}
+	}
+
+	t.stack = append(slices.Clip(t.stack), msg)
+}
+
+// backMsg goes back to the tab's previous view, or to parent when there's
+// none, for views that can be opened from more than one place
+type backMsg struct {
+	parent tea.Msg
+}
+
+// title is the name the user gave the tab, or else what it shows
+func (t tab) title() string {
+	if t.name != "" {
+		return t.name
+	}
+
+	title := tui.NamespaceTitle(t.data.Namespace)
+
+	switch msg := t.current().(type) {
+	case nil, tui.NamespacesViewMsg, tui.NamespacePromptViewMsg:
+		title = "namespaces"
+	case tui.ContainerLogsViewMsg:
+		title = msg.Container.Pod + "/" + msg.Container.Name
+	case tui.SplitViewMsg:
+		title = msg.Left.Name + " | " + msg.Right.Name
+	case tui.FilesViewMsg:
+		title = "files"
+	case tui.SessionViewMsg, tui.ReplayLogsViewMsg:
+		title = "replay"
+	case tui.FileLogsViewMsg:
+		title = filepath.Base(msg.Path)
+		if msg.Path == local.StdinPath {
+			title = "stdin"
+		}
+	case tui.CronJobJobsViewMsg,
+		tui.CronJobContainersViewMsg,
+		tui.CronJobLogsViewMsg,
+		tui.JobDiffViewMsg:
+		title = t.data.CronJob.Name
+	}
+
+	if r := []rune(title); len(r) > maxTabTitle {
+		title = string(r[:maxTabTitle-1]) + "…"
+	}
+
+	return title
+}
+
+// tabMsg is a message produced by a tab's view. it goes back to that tab,
+// even when another tab is active by the time it arrives.
+type tabMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+func routeToTab(id int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			for i := range msg {
+				msg[i] = routeToTab(id, msg[i])
+			}
+			return msg
+		case tea.QuitMsg:
+			return msg
+		default:
+			return tabMsg{id: id, msg: msg}
+		}
+	}
+}
+
+// newLines counts the log lines msg brings to a view
+func newLines(msg tea.Msg) int {
+	switch msg := msg.(type) {
+	case linesMsg:
+		n := 0
+		for _, r := range msg.results {
+			if r.Err == nil {
+				n++
+			}
+		}
+		return n
+	case tui.ItemsMsg:
+		n := 0
+		for _, item := range msg.Items {
+			if _, ok := item.(tui.Log); ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	return 0
+}
+
+func (m mainModel) tabBar() string {
+	active := lipgloss.
+		NewStyle().
+		Padding(0, 1).
+		Bold(true).
+		Foreground(tui.ActiveTheme.TitleCurrent)
+	inactive := lipgloss.
+		NewStyle().
+		Padding(0, 1).
+		Foreground(tui.ActiveTheme.Muted)
+	unread := lipgloss.
+		NewStyle().
+		Foreground(tui.ActiveTheme.Selected)
+
+	tabs := make([]string, len(m.tabs))
+
+	for i, t := range m.tabs {
+		title := fmt.Sprintf("%d %s", i+1, t.title())
+
+		if i == m.active && m.renaming {
+			title = fmt.Sprintf("%d %s", i+1, m.nameInput.View())
+		}
+
+		if i == m.active {
+			tabs[i] = active.Render(title)
+			continue
+		}
+
+		if t.unread > 0 {
+			title += unread.Render(fmt.Sprintf(" +%d", t.unread))
+		}
+
+		tabs[i] = inactive.Render(title)
+	}
+
+	return lipgloss.
+		NewStyle().
+		MaxWidth(m.size.Width).
+		Render(strings.Join(tabs, "│"))
+}
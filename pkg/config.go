@@ -1,30 +1,537 @@
 package pkg
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultTailLines       = 10
+	DefaultTimestampFormat = "2006-01-02T15:04:05"
+	DefaultGapThreshold    = time.Minute
+	DefaultArchiveInterval = time.Minute
 )
 
 type Config struct {
-	Kubeconfig string
+	Kubeconfig       string
+	Context          string
+	Namespace        string
+	Namespaces       []string
+	TailLines        int64
+	TimestampFormat  string
+	TimeZone         string
+	GapThreshold     time.Duration
+	Theme            string
+	HiddenNamespaces []string
+	Parsers          []ParserOverride
+	Multiline        MultilineConfig
+	Archive          ArchiveConfig
+	Keys             KeysConfig
+}
+
+// ArchiveConfig picks the cron jobs whose finished runs have their logs
+// archived before the job's pods are cleaned up, and how long archived runs
+// are kept. zero MaxRuns or MaxAge keeps them forever.
+type ArchiveConfig struct {
+	// CronJobs are "<namespace>/<cron job>" patterns
+	CronJobs []string      `yaml:"cronJobs"`
+	Interval time.Duration `yaml:"interval"`
+	MaxRuns  int           `yaml:"maxRuns"`
+	MaxAge   time.Duration `yaml:"maxAge"`
+}
+
+// CheckCronJobPattern checks a "<namespace>/<cron job>" pattern of the
+// archive config
+func CheckCronJobPattern(p string) error {
+	if _, _, ok := strings.Cut(p, "/"); !ok {
+		return fmt.Errorf("%q isn't a <namespace>/<cron job> pattern", p)
+	}
+	if _, err := path.Match(p, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+	return nil
+}
+
+// KeysConfig picks a key binding preset and overrides the keys of
+// individual actions, e.g. {"back": ["esc", "backspace"]}.
+type KeysConfig struct {
+	Preset   string              `yaml:"preset"`
+	Bindings map[string][]string `yaml:"bindings"`
+}
+
+// ParserOverride forces a log format for containers whose
+// "<namespace>/<pod>/<container>" path matches Match.
+type ParserOverride struct {
+	Match  string `yaml:"match"`
+	Format string `yaml:"format"`
+}
+
+var ParserFormats = []string{"json", "logfmt", "plain"}
+
+type settings struct {
+	Kubeconfig       string           `yaml:"kubeconfig"`
+	Context          string           `yaml:"context"`
+	Namespace        string           `yaml:"namespace"`
+	Namespaces       []string         `yaml:"namespaces"`
+	TailLines        int64            `yaml:"tailLines"`
+	TimestampFormat  string           `yaml:"timestampFormat"`
+	TimeZone         string           `yaml:"timeZone"`
+	GapThreshold     time.Duration    `yaml:"gapThreshold"`
+	Theme            string           `yaml:"theme"`
+	HiddenNamespaces []string         `yaml:"hiddenNamespaces"`
+	Parsers          []ParserOverride `yaml:"parsers"`
+	Multiline        MultilineConfig  `yaml:"multiline"`
+	Archive          ArchiveConfig    `yaml:"archive"`
+	Keys             KeysConfig       `yaml:"keys"`
+}
+
+type configFile struct {
+	settings `yaml:",inline"`
+	Profile  string              `yaml:"profile"`
+	Profiles map[string]settings `yaml:"profiles"`
+}
+
+type ConfigError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e ConfigError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e ConfigError) Unwrap() error {
+	return e.Err
 }
 
-func LoadConfig() (Config, error) {
+func ConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+
+	if dir == "" {
+		homedir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("get user home dir: %w", err)
+		}
+
+		dir = filepath.Join(homedir, ".config")
+	}
+
+	return filepath.Join(dir, "log-viewer", "config.yaml"), nil
+}
+
+// LoadConfig merges the config file, the selected profile and the
+// environment. An empty profile selects the file's "profile" key, if any.
+// $KUBECONFIG only applies when neither the file nor the profile set one.
+func LoadConfig(profile string) (Config, error) {
 	godotenv.Load()
 
-	kubeconfig := os.Getenv("KUBECONFIG")
+	configPath, err := ConfigPath()
+	if err != nil {
+		return Config{}, err
+	}
+
+	s, err := loadSettings(configPath, profile)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		Kubeconfig:       s.Kubeconfig,
+		Context:          s.Context,
+		Namespace:        s.Namespace,
+		Namespaces:       s.Namespaces,
+		TailLines:        s.TailLines,
+		TimestampFormat:  s.TimestampFormat,
+		TimeZone:         s.TimeZone,
+		GapThreshold:     s.GapThreshold,
+		Theme:            s.Theme,
+		HiddenNamespaces: s.HiddenNamespaces,
+		Parsers:          s.Parsers,
+		Multiline:        s.Multiline,
+		Archive:          s.Archive,
+		Keys:             s.Keys,
+	}
+
+	// a kubeconfig picked by the file or profile wins over the environment,
+	// or a profile's context would be looked up in the shell's cluster
+	if cfg.Kubeconfig == "" {
+		cfg.Kubeconfig = os.Getenv("KUBECONFIG")
+	}
 
-	if kubeconfig == "" {
+	if cfg.Kubeconfig == "" {
 		homedir, err := os.UserHomeDir()
 		if err != nil {
 			return Config{}, fmt.Errorf("get user home dir: %w", err)
 		}
 
-		kubeconfig = filepath.Join(homedir, ".kube", "config")
+		cfg.Kubeconfig = filepath.Join(homedir, ".kube", "config")
+	}
+
+	if cfg.TailLines == 0 {
+		cfg.TailLines = DefaultTailLines
+	}
+	if cfg.TimestampFormat == "" {
+		cfg.TimestampFormat = DefaultTimestampFormat
+	}
+	if cfg.GapThreshold == 0 {
+		cfg.GapThreshold = DefaultGapThreshold
+	}
+	if cfg.Archive.Interval == 0 {
+		cfg.Archive.Interval = DefaultArchiveInterval
+	}
+
+	return cfg, nil
+}
+
+func loadSettings(configPath string, profile string) (settings, error) {
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if profile != "" {
+			return settings{}, fmt.Errorf(
+				"profile %q: no config file at %s",
+				profile,
+				configPath,
+			)
+		}
+		return settings{}, nil
+	}
+	if err != nil {
+		return settings{}, fmt.Errorf("read config: %w", err)
+	}
+
+	file, lines, err := parseConfigFile(data)
+	if err != nil {
+		return settings{}, yamlConfigError(configPath, err)
+	}
+
+	v := configValidator{file: configPath, lines: lines}
+
+	v.settings("", file.settings)
+
+	for name, p := range file.Profiles {
+		v.settings("profiles."+name+".", p)
+	}
+
+	if profile == "" {
+		profile = file.Profile
+	}
+
+	s := file.settings
+
+	if profile != "" {
+		p, ok := file.Profiles[profile]
+
+		switch {
+		case ok:
+			s = s.merge(p)
+		case file.Profile == profile:
+			v.fail("profile", "unknown profile %q", profile)
+		default:
+			v.fail("profiles", "unknown profile %q", profile)
+		}
+	}
+
+	if err := errors.Join(v.errs...); err != nil {
+		return settings{}, err
+	}
+
+	s.Kubeconfig = expandHome(s.Kubeconfig)
+
+	// a theme file is found next to the config, wherever the command runs
+	if IsThemePath(s.Theme) {
+		s.Theme = expandHome(s.Theme)
+		if !filepath.IsAbs(s.Theme) {
+			s.Theme = filepath.Join(filepath.Dir(configPath), s.Theme)
+		}
+	}
+
+	return s, nil
+}
+
+// IsThemePath tells a theme file's path from the name of a built-in theme
+func IsThemePath(name string) bool {
+	ext := filepath.Ext(name)
+	return strings.ContainsRune(name, filepath.Separator) ||
+		strings.HasPrefix(name, "~") ||
+		ext == ".yaml" ||
+		ext == ".yml"
+}
+
+func parseConfigFile(data []byte) (configFile, map[string]int, error) {
+	file := configFile{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&file); err != nil {
+		return configFile{}, nil, err
+	}
+
+	root := yaml.Node{}
+
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return configFile{}, nil, err
+	}
+
+	lines := map[string]int{}
+	collectLines(&root, "", lines)
+
+	return file, lines, nil
+}
+
+// collectLines records the line of every mapping key and sequence item by
+// its dotted path, e.g. "profiles.prod.parsers.0.format".
+func collectLines(node *yaml.Node, prefix string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			collectLines(n, prefix, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := prefix + node.Content[i].Value
+			lines[key] = node.Content[i].Line
+			collectLines(node.Content[i+1], key+".", lines)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			key := prefix + strconv.Itoa(i)
+			lines[key] = n.Line
+			collectLines(n, key+".", lines)
+		}
+	}
+}
+
+var (
+	yamlLineError    = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlUnknownField = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+)
+
+func yamlConfigError(file string, err error) error {
+	messages := []string{err.Error()}
+
+	if te := (*yaml.TypeError)(nil); errors.As(err, &te) {
+		messages = te.Errors
+	}
+
+	errs := []error{}
+
+	for _, msg := range messages {
+		ce := ConfigError{File: file, Err: errors.New(msg)}
+
+		if m := yamlLineError.FindStringSubmatch(msg); m != nil {
+			ce.Line, _ = strconv.Atoi(m[1])
+			ce.Err = errors.New(m[2])
+
+			if m := yamlUnknownField.FindStringSubmatch(m[2]); m != nil {
+				ce.Err = fmt.Errorf("unknown field %q", m[1])
+			}
+		}
+
+		errs = append(errs, ce)
+	}
+
+	return errors.Join(errs...)
+}
+
+type configValidator struct {
+	file  string
+	lines map[string]int
+	errs  []error
+}
+
+func (v *configValidator) fail(key string, format string, args ...any) {
+	v.errs = append(v.errs, ConfigError{
+		File: v.file,
+		Line: v.lines[key],
+		Err:  fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)),
+	})
+}
+
+func (v *configValidator) settings(prefix string, s settings) {
+	if s.TailLines < -1 {
+		v.fail(
+			prefix+"tailLines",
+			"must be -1 for all lines, 0 for the default or a positive number, got %d",
+			s.TailLines,
+		)
+	}
+
+	if f := s.TimestampFormat; f != "" && (time.Time{}).Format(f) == f {
+		v.fail(
+			prefix+"timestampFormat",
+			"%q has no Go time layout elements (e.g. 2006-01-02 15:04:05)",
+			s.TimestampFormat,
+		)
+	}
+
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			v.fail(prefix+"timeZone", "unknown time zone %q", s.TimeZone)
+		}
+	}
+
+	if s.GapThreshold < 0 {
+		v.fail(prefix+"gapThreshold", "must be positive, got %s", s.GapThreshold)
+	}
+
+	for i, namespace := range s.Namespaces {
+		if strings.TrimSpace(namespace) == "" {
+			v.fail(
+				fmt.Sprintf("%snamespaces.%d", prefix, i),
+				"namespace can't be empty",
+			)
+		}
+	}
+
+	for i, namespace := range s.HiddenNamespaces {
+		if strings.TrimSpace(namespace) == "" {
+			v.fail(
+				fmt.Sprintf("%shiddenNamespaces.%d", prefix, i),
+				"namespace can't be empty",
+			)
+		}
+	}
+
+	for i, p := range s.Parsers {
+		key := fmt.Sprintf("%sparsers.%d", prefix, i)
+
+		if p.Match == "" {
+			v.fail(key, "match is required")
+		} else if _, err := path.Match(p.Match, ""); err != nil {
+			v.fail(key+".match", "invalid pattern %q: %v", p.Match, err)
+		}
+
+		if !slices.Contains(ParserFormats, p.Format) {
+			v.fail(
+				key+".format",
+				"unknown format %q (want one of %s)",
+				p.Format,
+				strings.Join(ParserFormats, ", "),
+			)
+		}
+	}
+
+	for i, c := range s.Multiline.Continuations {
+		if _, err := regexp.Compile(c); err != nil {
+			v.fail(
+				fmt.Sprintf("%smultiline.continuations.%d", prefix, i),
+				"invalid regex %q: %v",
+				c,
+				err,
+			)
+		}
+	}
+
+	for i, p := range s.Archive.CronJobs {
+		if err := CheckCronJobPattern(p); err != nil {
+			v.fail(fmt.Sprintf("%sarchive.cronJobs.%d", prefix, i), "%v", err)
+		}
+	}
+
+	if s.Archive.Interval < 0 {
+		v.fail(prefix+"archive.interval", "must be positive, got %s", s.Archive.Interval)
+	}
+	if s.Archive.MaxRuns < 0 {
+		v.fail(prefix+"archive.maxRuns", "must be positive, got %d", s.Archive.MaxRuns)
+	}
+	if s.Archive.MaxAge < 0 {
+		v.fail(prefix+"archive.maxAge", "must be positive, got %s", s.Archive.MaxAge)
+	}
+}
+
+func (s settings) merge(o settings) settings {
+	if o.Kubeconfig != "" {
+		s.Kubeconfig = o.Kubeconfig
+	}
+	if o.Context != "" {
+		s.Context = o.Context
+	}
+	if o.Namespace != "" {
+		s.Namespace = o.Namespace
+	}
+	if o.Namespaces != nil {
+		s.Namespaces = o.Namespaces
+	}
+	if o.TailLines != 0 {
+		s.TailLines = o.TailLines
+	}
+	if o.TimestampFormat != "" {
+		s.TimestampFormat = o.TimestampFormat
+	}
+	if o.TimeZone != "" {
+		s.TimeZone = o.TimeZone
+	}
+	if o.GapThreshold != 0 {
+		s.GapThreshold = o.GapThreshold
+	}
+	if o.Theme != "" {
+		s.Theme = o.Theme
+	}
+	if o.HiddenNamespaces != nil {
+		s.HiddenNamespaces = o.HiddenNamespaces
+	}
+	if o.Parsers != nil {
+		s.Parsers = o.Parsers
+	}
+	if o.Multiline.Builtins != nil {
+		s.Multiline.Builtins = o.Multiline.Builtins
+	}
+	if o.Multiline.Continuations != nil {
+		s.Multiline.Continuations = o.Multiline.Continuations
+	}
+	if o.Archive.CronJobs != nil {
+		s.Archive.CronJobs = o.Archive.CronJobs
+	}
+	if o.Archive.Interval != 0 {
+		s.Archive.Interval = o.Archive.Interval
+	}
+	if o.Archive.MaxRuns != 0 {
+		s.Archive.MaxRuns = o.Archive.MaxRuns
+	}
+	if o.Archive.MaxAge != 0 {
+		s.Archive.MaxAge = o.Archive.MaxAge
+	}
+	if o.Keys.Preset != "" {
+		s.Keys.Preset = o.Keys.Preset
+	}
+	if o.Keys.Bindings != nil {
+		bindings := maps.Clone(s.Keys.Bindings)
+		if bindings == nil {
+			bindings = map[string][]string{}
+		}
+		maps.Copy(bindings, o.Keys.Bindings)
+		s.Keys.Bindings = bindings
+	}
+	return s
+}
+
+func expandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return p
 	}
 
-	return Config{Kubeconfig: kubeconfig}, nil
+	return filepath.Join(homedir, strings.TrimPrefix(p, "~"))
 }
@@ -0,0 +1,241 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	configPath := filepath.Join(dir, "log-viewer", "config.yaml")
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return configPath
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		profile string
+		want    []string
+	}{
+		{
+			name:   "negative tail lines",
+			config: "tailLines: -2\n",
+			want:   []string{":1: tailLines: must be -1 for all lines, 0 for the default or a positive number"},
+		},
+		{
+			name:   "profile field",
+			config: "profiles:\n  prod:\n    timeZone: Nowhere/Else\n",
+			want:   []string{":3: profiles.prod.timeZone: unknown time zone"},
+		},
+		{
+			name:   "sequence item",
+			config: "parsers:\n  - match: \"*\"\n    format: xml\n",
+			want:   []string{":3: parsers.0.format: unknown format \"xml\""},
+		},
+		{
+			name:   "every error",
+			config: "gapThreshold: -1s\narchive:\n  cronJobs: [nightly]\n  maxRuns: -1\n",
+			want: []string{
+				":1: gapThreshold: must be positive",
+				":3: archive.cronJobs.0:",
+				":4: archive.maxRuns: must be positive",
+			},
+		},
+		{
+			name:   "unknown field",
+			config: "context: a\ncolour: red\n",
+			want:   []string{":2: unknown field \"colour\""},
+		},
+		{
+			name:    "unknown profile",
+			config:  "profiles:\n  prod: {}\n",
+			profile: "staging",
+			want:    []string{":1: profiles: unknown profile \"staging\""},
+		},
+		{
+			name:   "unknown default profile",
+			config: "profile: staging\n",
+			want:   []string{":1: profile: unknown profile \"staging\""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeConfig(t, tt.config)
+
+			_, err := loadSettings(configPath, tt.profile)
+			if err == nil {
+				t.Fatal("want an error, got none")
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), configPath+want) {
+					t.Errorf("error %q doesn't contain %q", err, configPath+want)
+				}
+			}
+
+			if got := strings.Count(err.Error(), "\n") + 1; got != len(tt.want) {
+				t.Errorf("got %d errors, want %d: %v", got, len(tt.want), err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMerge(t *testing.T) {
+	config := `kubeconfig: /file/kubeconfig
+context: file
+namespace: default
+tailLines: 20
+profiles:
+  prod:
+    kubeconfig: /prod/kubeconfig
+    context: prod
+  staging:
+    context: staging
+    tailLines: 50
+`
+
+	tests := []struct {
+		name       string
+		config     string
+		profile    string
+		env        string
+		kubeconfig string
+		context    string
+		tailLines  int64
+	}{
+		{
+			name:       "file",
+			config:     config,
+			env:        "/env/kubeconfig",
+			kubeconfig: "/file/kubeconfig",
+			context:    "file",
+			tailLines:  20,
+		},
+		{
+			name:       "profile wins over file and environment",
+			config:     config,
+			profile:    "prod",
+			env:        "/env/kubeconfig",
+			kubeconfig: "/prod/kubeconfig",
+			context:    "prod",
+			tailLines:  20,
+		},
+		{
+			name:       "profile inherits the file's kubeconfig",
+			config:     config,
+			profile:    "staging",
+			env:        "/env/kubeconfig",
+			kubeconfig: "/file/kubeconfig",
+			context:    "staging",
+			tailLines:  50,
+		},
+		{
+			name:       "default profile",
+			config:     "profile: prod\n" + config,
+			env:        "/env/kubeconfig",
+			kubeconfig: "/prod/kubeconfig",
+			context:    "prod",
+			tailLines:  20,
+		},
+		{
+			name:       "environment when nothing sets a kubeconfig",
+			config:     "context: file\n",
+			env:        "/env/kubeconfig",
+			kubeconfig: "/env/kubeconfig",
+			context:    "file",
+			tailLines:  DefaultTailLines,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t, tt.config)
+			t.Setenv("KUBECONFIG", tt.env)
+
+			cfg, err := LoadConfig(tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Kubeconfig != tt.kubeconfig {
+				t.Errorf("kubeconfig = %q, want %q", cfg.Kubeconfig, tt.kubeconfig)
+			}
+			if cfg.Context != tt.context {
+				t.Errorf("context = %q, want %q", cfg.Context, tt.context)
+			}
+			if cfg.TailLines != tt.tailLines {
+				t.Errorf("tailLines = %d, want %d", cfg.TailLines, tt.tailLines)
+			}
+		})
+	}
+}
+
+func TestLoadConfigTheme(t *testing.T) {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		theme string
+		want  func(configDir string) string
+	}{
+		{
+			theme: "light",
+			want:  func(string) string { return "light" },
+		},
+		{
+			theme: "themes/mine.yaml",
+			want: func(configDir string) string {
+				return filepath.Join(configDir, "themes", "mine.yaml")
+			},
+		},
+		{
+			theme: "mine.yml",
+			want: func(configDir string) string {
+				return filepath.Join(configDir, "mine.yml")
+			},
+		},
+		{
+			theme: "~/themes/mine.yaml",
+			want: func(string) string {
+				return filepath.Join(homedir, "themes", "mine.yaml")
+			},
+		},
+		{
+			theme: "/etc/log-viewer/mine.yaml",
+			want:  func(string) string { return "/etc/log-viewer/mine.yaml" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.theme, func(t *testing.T) {
+			configPath := writeConfig(t, "theme: "+tt.theme+"\n")
+
+			cfg, err := LoadConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := tt.want(filepath.Dir(configPath)); cfg.Theme != want {
+				t.Errorf("theme = %q, want %q", cfg.Theme, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package pkg
+
+import (
+	"context"
+	"time"
+)
+
+// LogMeta describes where the lines of a log source come from. sources
+// outside a cluster name their origin in the same terms, e.g. a local
+// file's directory is its Pod.
+type LogMeta struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container"`
+	// Start is when the logging began, e.g. a job's start time, if known
+	Start time.Time `json:"start"`
+}
+
+// LogSource is where log views get their lines from: a pod's log API, a
+// local file, stdin, ...
+type LogSource interface {
+	// Meta describes the source
+	Meta() LogMeta
+	// Open reads the latest lines, e.g. a configured tail
+	Open(ctx context.Context) ([]string, error)
+	// Seek reads the lines logged since t. sources that can't seek by time
+	// return what Open does, and leave finding t to the view.
+	Seek(ctx context.Context, t time.Time) ([]string, error)
+	// Follow sends the lines Open reads, then new ones as they're logged,
+	// until ctx is done or the source ends. the channel is closed after.
+	Follow(ctx context.Context) <-chan Result[string]
+	// Close releases what the source holds once it's no longer read
+	Close() error
+}
+
+// LogPlayer is implemented by sources that play their lines back over
+// time, e.g. a recorded session, so views can pause them
+type LogPlayer interface {
+	// TogglePause pauses or resumes the playback, and reports whether it's
+	// paused
+	TogglePause() bool
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MultilineConfig sets how lines are grouped into multi-line entries such as
+// stack traces
+type MultilineConfig struct {
+	// Builtins enables the Go, Java, Python and Node trace rules. nil
+	// leaves them on.
+	Builtins *bool `yaml:"builtins"`
+	// Continuations are regexes for lines that continue the entry before
+	// them
+	Continuations []string `yaml:"continuations"`
+}
+
+var (
+	// indented lines continue nearly every trace format, e.g. Go's file
+	// lines, Java's and Node's "at" frames and Python's "File" lines
+	indentedLine = regexp.MustCompile(`^[ \t]+\S`)
+
+	goTraceStart = regexp.MustCompile(`^(panic: |fatal error: |goroutine \d+ \[)`)
+	goTraceLine  = regexp.MustCompile(
+		`^(goroutine \d+ \[.*\]:$|created by |\[signal |panic: |exit status \d+$|[\w./*()\[\]{}-]+\(.*\)$)`,
+	)
+
+	javaTraceLine = regexp.MustCompile(`^(Caused by: |\s*Suppressed: )`)
+
+	pythonTraceStart = regexp.MustCompile(`^Traceback \(most recent call last\):`)
+	pythonChain      = regexp.MustCompile(
+		`^(During handling of the above exception, another exception occurred:|The above exception was the direct cause of the following exception:)$`,
+	)
+)
+
+// Multiline decides which lines continue the entry logged before them
+type Multiline struct {
+	builtins      bool
+	continuations []*regexp.Regexp
+}
+
+func DefaultMultiline() Multiline {
+	return Multiline{builtins: true}
+}
+
+func NewMultiline(cfg MultilineConfig) (Multiline, error) {
+	m := Multiline{builtins: cfg.Builtins == nil || *cfg.Builtins}
+
+	for _, c := range cfg.Continuations {
+		re, err := regexp.Compile(c)
+		if err != nil {
+			return Multiline{}, fmt.Errorf("compile continuation %q: %w", c, err)
+		}
+		m.continuations = append(m.continuations, re)
+	}
+
+	return m, nil
+}
+
+// Continues reports whether line belongs to entry, the lines grouped so far
+func (m Multiline) Continues(entry []string, line string) bool {
+	if len(entry) == 0 {
+		return false
+	}
+
+	for _, re := range m.continuations {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+
+	if !m.builtins {
+		return false
+	}
+
+	first, prev := entry[0], entry[len(entry)-1]
+
+	goTrace := goTraceStart.MatchString(first)
+	pythonTrace := pythonTraceStart.MatchString(first)
+
+	switch {
+	// blank lines separate goroutines and chained python exceptions
+	case strings.TrimSpace(line) == "":
+		return goTrace || pythonTrace
+	case indentedLine.MatchString(line):
+		return true
+	case javaTraceLine.MatchString(line):
+		return true
+	case goTrace:
+		return goTraceLine.MatchString(line)
+	case pythonTrace:
+		return pythonChain.MatchString(line) ||
+			// the exception line ends the frames
+			indentedLine.MatchString(prev) ||
+			(pythonTraceStart.MatchString(line) &&
+				(strings.TrimSpace(prev) == "" || pythonChain.MatchString(prev)))
+	}
+
+	return false
+}
+
+// Group joins lines into entries. blank lines that don't continue an entry
+// are dropped.
+func (m Multiline) Group(lines []string) [][]string {
+	entries := [][]string{}
+
+	for _, line := range lines {
+		if n := len(entries); n > 0 && m.Continues(entries[n-1], line) {
+			entries[n-1] = append(entries[n-1], line)
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entries = append(entries, []string{line})
+	}
+
+	return entries
+}
+
+// JoinEntry joins the lines of an entry without the blank lines that
+// trail it
+func JoinEntry(entry []string) string {
+	return strings.TrimRight(strings.Join(entry, "\n"), " \t\n")
+}
@@ -0,0 +1,297 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContinues(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry []string
+		line  string
+		want  bool
+	}{
+		{
+			name: "no entry",
+			line: "\tat com.example.Main.run(Main.java:10)",
+			want: false,
+		},
+		{
+			name:  "plain line",
+			entry: []string{"INFO started"},
+			line:  "INFO listening on :8080",
+			want:  false,
+		},
+		{
+			name:  "go goroutine header",
+			entry: []string{"panic: runtime error: index out of range [3] with length 3", ""},
+			line:  "goroutine 1 [running]:",
+			want:  true,
+		},
+		{
+			name:  "go function line",
+			entry: []string{"panic: boom", "", "goroutine 1 [running]:"},
+			line:  "main.handle(0xc000012345, 0x3)",
+			want:  true,
+		},
+		{
+			name:  "go file line",
+			entry: []string{"panic: boom", "", "goroutine 1 [running]:", "main.main()"},
+			line:  "\t/app/main.go:12 +0x1d",
+			want:  true,
+		},
+		{
+			name:  "go blank line between goroutines",
+			entry: []string{"fatal error: all goroutines are asleep - deadlock!"},
+			line:  "",
+			want:  true,
+		},
+		{
+			name:  "go exit status",
+			entry: []string{"panic: boom", "", "goroutine 1 [running]:", "main.main()"},
+			line:  "exit status 2",
+			want:  true,
+		},
+		{
+			name:  "go trace ends at a log line",
+			entry: []string{"panic: boom", "", "goroutine 1 [running]:", "main.main()"},
+			line:  "2024/01/01 12:00:00 restarting",
+			want:  false,
+		},
+		{
+			name:  "java frame",
+			entry: []string{"java.lang.IllegalStateException: boom"},
+			line:  "\tat com.example.Main.run(Main.java:10)",
+			want:  true,
+		},
+		{
+			name:  "java cause",
+			entry: []string{"java.lang.IllegalStateException: boom", "\tat com.example.Main.run(Main.java:10)"},
+			line:  "Caused by: java.io.IOException: closed",
+			want:  true,
+		},
+		{
+			name:  "java suppressed",
+			entry: []string{"java.lang.IllegalStateException: boom", "\tat com.example.Main.run(Main.java:10)"},
+			line:  "\tSuppressed: java.io.IOException: closed",
+			want:  true,
+		},
+		{
+			name:  "java blank line ends the trace",
+			entry: []string{"java.lang.IllegalStateException: boom", "\tat com.example.Main.run(Main.java:10)"},
+			line:  "",
+			want:  false,
+		},
+		{
+			name:  "python frame",
+			entry: []string{"Traceback (most recent call last):"},
+			line:  `  File "app.py", line 3, in <module>`,
+			want:  true,
+		},
+		{
+			name: "python exception line",
+			entry: []string{
+				"Traceback (most recent call last):",
+				`  File "app.py", line 3, in <module>`,
+				"    main()",
+			},
+			line: "ValueError: bad",
+			want: true,
+		},
+		{
+			name: "python line after the exception",
+			entry: []string{
+				"Traceback (most recent call last):",
+				`  File "app.py", line 3, in <module>`,
+				"    main()",
+				"ValueError: bad",
+			},
+			line: "INFO retrying",
+			want: false,
+		},
+		{
+			name: "python chained exception",
+			entry: []string{
+				"Traceback (most recent call last):",
+				`  File "app.py", line 3, in <module>`,
+				"ValueError: bad",
+				"",
+			},
+			line: "During handling of the above exception, another exception occurred:",
+			want: true,
+		},
+		{
+			name: "python chained traceback",
+			entry: []string{
+				"Traceback (most recent call last):",
+				`  File "app.py", line 3, in <module>`,
+				"ValueError: bad",
+				"",
+				"The above exception was the direct cause of the following exception:",
+				"",
+			},
+			line: "Traceback (most recent call last):",
+			want: true,
+		},
+		{
+			name:  "node frame",
+			entry: []string{"TypeError: Cannot read properties of undefined (reading 'id')"},
+			line:  "    at handler (/app/index.js:12:20)",
+			want:  true,
+		},
+		{
+			name:  "node trace ends at a log line",
+			entry: []string{"TypeError: boom", "    at handler (/app/index.js:12:20)"},
+			line:  `{"level":"info","msg":"retrying"}`,
+			want:  false,
+		},
+	}
+
+	m := DefaultMultiline()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Continues(tt.entry, tt.line); got != tt.want {
+				t.Errorf("Continues(%q, %q) = %v, want %v", tt.entry, tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContinuesConfig(t *testing.T) {
+	off := false
+
+	tests := []struct {
+		name string
+		cfg  MultilineConfig
+		line string
+		want bool
+	}{
+		{
+			name: "builtins on by default",
+			line: "\tat com.example.Main.run(Main.java:10)",
+			want: true,
+		},
+		{
+			name: "builtins off",
+			cfg:  MultilineConfig{Builtins: &off},
+			line: "\tat com.example.Main.run(Main.java:10)",
+			want: false,
+		},
+		{
+			name: "continuation",
+			cfg:  MultilineConfig{Builtins: &off, Continuations: []string{`^\.\.\. `}},
+			line: "... 3 more",
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMultiline(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := m.Continues([]string{"ERROR failed"}, tt.line); got != tt.want {
+				t.Errorf("Continues(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines string
+		want  [][]string
+	}{
+		{
+			name:  "plain lines",
+			lines: "one\ntwo",
+			want:  [][]string{{"one"}, {"two"}},
+		},
+		{
+			name:  "blank lines outside a trace are dropped",
+			lines: "one\n\n  \ntwo",
+			want:  [][]string{{"one"}, {"two"}},
+		},
+		{
+			name: "go panic",
+			lines: "starting\n" +
+				"panic: boom\n" +
+				"\n" +
+				"goroutine 1 [running]:\n" +
+				"main.main()\n" +
+				"\t/app/main.go:12 +0x1d\n" +
+				"exit status 2\n" +
+				"restarting",
+			want: [][]string{
+				{"starting"},
+				{
+					"panic: boom",
+					"",
+					"goroutine 1 [running]:",
+					"main.main()",
+					"\t/app/main.go:12 +0x1d",
+					"exit status 2",
+				},
+				{"restarting"},
+			},
+		},
+		{
+			name: "java trace with a cause",
+			lines: "java.lang.IllegalStateException: boom\n" +
+				"\tat com.example.Main.run(Main.java:10)\n" +
+				"Caused by: java.io.IOException: closed\n" +
+				"\t... 3 more\n" +
+				"done",
+			want: [][]string{
+				{
+					"java.lang.IllegalStateException: boom",
+					"\tat com.example.Main.run(Main.java:10)",
+					"Caused by: java.io.IOException: closed",
+					"\t... 3 more",
+				},
+				{"done"},
+			},
+		},
+		{
+			name: "python trace",
+			lines: "Traceback (most recent call last):\n" +
+				`  File "app.py", line 3, in <module>` + "\n" +
+				"    main()\n" +
+				"ValueError: bad\n" +
+				"retrying",
+			want: [][]string{
+				{
+					"Traceback (most recent call last):",
+					`  File "app.py", line 3, in <module>`,
+					"    main()",
+					"ValueError: bad",
+				},
+				{"retrying"},
+			},
+		},
+	}
+
+	m := DefaultMultiline()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Group(strings.Split(tt.lines, "\n"))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Group() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinEntry(t *testing.T) {
+	got := JoinEntry([]string{"panic: boom", "", "goroutine 1 [running]:", "", ""})
+	if want := "panic: boom\n\ngoroutine 1 [running]:"; got != want {
+		t.Errorf("JoinEntry() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,174 @@
+package session
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// Recorder writes the lines of every source it records to a session file
+type Recorder struct {
+	mu         sync.Mutex
+	f          *os.File
+	gz         *gzip.Writer
+	enc        *json.Encoder
+	started    time.Time
+	lastStream int
+	// opened has the streams whose meta was written. it's written with the
+	// first lines, so sources that send none, e.g. ones only asked for
+	// their meta, aren't recorded.
+	opened map[int]bool
+	err    error
+	closed bool
+	// follows are the goroutines passing on the lines of followed sources,
+	// which Close waits for
+	follows sync.WaitGroup
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create session dir: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	gz := gzip.NewWriter(f)
+
+	r := &Recorder{
+		f:       f,
+		gz:      gz,
+		enc:     json.NewEncoder(gz),
+		started: time.Now(),
+		opened:  map[int]bool{},
+	}
+
+	if err := r.enc.Encode(header{Version: version, Started: r.started}); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("write session header: %w", err)
+	}
+
+	return r, nil
+}
+
+// Record wraps source so everything it sends is recorded as a new stream
+func (r *Recorder) Record(source pkg.LogSource) pkg.LogSource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastStream++
+
+	return recordedSource{LogSource: source, recorder: r, stream: r.lastStream}
+}
+
+// Close finishes the session file once the followed sources ended, so call
+// it after cancelling their contexts. it reports the first error recording
+// ran into, since recording doesn't interrupt the sources.
+func (r *Recorder) Close() error {
+	r.follows.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+
+	if err := r.gz.Close(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("close session: %w", err)
+	}
+	if err := r.f.Close(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("close session: %w", err)
+	}
+
+	return r.err
+}
+
+func (r *Recorder) lines(stream int, meta pkg.LogMeta, lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	if !r.opened[stream] {
+		r.opened[stream] = true
+		r.write(record{Stream: stream, Meta: &meta})
+	}
+
+	offset := time.Since(r.started).Milliseconds()
+
+	for _, line := range lines {
+		r.write(record{Stream: stream, Offset: offset, Line: line})
+	}
+
+	// flushed, so a session that's cut off still has what came before
+	if err := r.gz.Flush(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("flush session: %w", err)
+	}
+}
+
+func (r *Recorder) write(rec record) {
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(rec); err != nil {
+		r.err = fmt.Errorf("write session: %w", err)
+	}
+}
+
+type recordedSource struct {
+	pkg.LogSource
+	recorder *Recorder
+	stream   int
+}
+
+func (s recordedSource) Open(ctx context.Context) ([]string, error) {
+	lines, err := s.LogSource.Open(ctx)
+	s.recorder.lines(s.stream, s.Meta(), lines...)
+	return lines, err
+}
+
+func (s recordedSource) Seek(ctx context.Context, t time.Time) ([]string, error) {
+	lines, err := s.LogSource.Seek(ctx, t)
+	s.recorder.lines(s.stream, s.Meta(), lines...)
+	return lines, err
+}
+
+func (s recordedSource) Follow(ctx context.Context) <-chan pkg.Result[string] {
+	in := s.LogSource.Follow(ctx)
+	out := make(chan pkg.Result[string], cap(in))
+
+	s.recorder.follows.Add(1)
+
+	go func() {
+		defer s.recorder.follows.Done()
+		defer close(out)
+
+		// only lines that were passed on are recorded. the source closes in
+		// once it sees ctx is done.
+		for r := range in {
+			select {
+			case out <- r:
+				if r.Err == nil {
+					s.recorder.lines(s.stream, s.Meta(), r.V)
+				}
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out
+}
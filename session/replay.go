@@ -0,0 +1,218 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// Replay plays a recorded stream back as a log source, at the pace its
+// lines arrived divided by speed. a speed of zero or less plays it back
+// instantly.
+type Replay struct {
+	stream Stream
+	found  bool
+	// since is where the playback starts. the lines before it are sent
+	// right away.
+	since time.Time
+	speed float64
+
+	mu     sync.Mutex
+	paused bool
+	// changed is closed, then replaced, whenever the playback is paused or
+	// resumed
+	changed chan struct{}
+}
+
+// Replay plays the stream with id back from since
+func (s *Session) Replay(id int, since time.Time, speed float64) *Replay {
+	stream, found := s.Stream(id)
+	if !found {
+		stream.ID = id
+		stream.Meta.Container = fmt.Sprintf("stream %d", id)
+	}
+
+	return &Replay{
+		stream:  stream,
+		found:   found,
+		since:   since,
+		speed:   speed,
+		changed: make(chan struct{}),
+	}
+}
+
+// Meta is the recorded meta. the start of streams that don't know theirs
+// is the first line, so "start" goes to the start of the recording.
+func (r *Replay) Meta() pkg.LogMeta {
+	meta := r.stream.Meta
+	if meta.Start.IsZero() && len(r.stream.Lines) > 0 {
+		meta.Start = loggedAt(r.stream.Lines[0])
+	}
+	return meta
+}
+
+// Open returns every line of the stream
+func (r *Replay) Open(ctx context.Context) ([]string, error) {
+	return r.Seek(ctx, time.Time{})
+}
+
+// Seek returns the lines of the stream from the first one logged at or
+// after t
+func (r *Replay) Seek(ctx context.Context, t time.Time) ([]string, error) {
+	if !r.found {
+		return nil, r.notFound()
+	}
+
+	lines := r.stream.Lines[r.index(t):]
+
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.Text
+	}
+
+	return texts, nil
+}
+
+func (r *Replay) Follow(ctx context.Context) <-chan pkg.Result[string] {
+	logsCh := make(chan pkg.Result[string], 256)
+	go r.play(ctx, logsCh)
+	return logsCh
+}
+
+func (r *Replay) Close() error {
+	return nil
+}
+
+// TogglePause pauses or resumes the playback, and reports whether it's
+// paused
+func (r *Replay) TogglePause() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.paused = !r.paused
+
+	close(r.changed)
+	r.changed = make(chan struct{})
+
+	return r.paused
+}
+
+func (r *Replay) notFound() error {
+	return fmt.Errorf("no stream %d in the session", r.stream.ID)
+}
+
+// index is the index of the first line logged at or after t
+func (r *Replay) index(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+
+	for i, l := range r.stream.Lines {
+		if !loggedAt(l).Before(t) {
+			return i
+		}
+	}
+
+	return len(r.stream.Lines)
+}
+
+// loggedAt is the time in a line's timestamp prefix, or else when it arrived
+func loggedAt(l Line) time.Time {
+	ts, _, ok := strings.Cut(l.Text, " ")
+	if !ok {
+		return l.At
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return l.At
+	}
+
+	return t
+}
+
+func (r *Replay) play(ctx context.Context, logsCh chan<- pkg.Result[string]) {
+	defer close(logsCh)
+
+	send := func(res pkg.Result[string]) bool {
+		select {
+		case logsCh <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if !r.found {
+		send(pkg.Result[string]{Err: r.notFound()})
+		return
+	}
+
+	lines := r.stream.Lines
+	start := r.index(r.since)
+
+	for _, l := range lines[:start] {
+		if !send(pkg.Result[string]{V: l.Text}) {
+			return
+		}
+	}
+
+	if start == len(lines) {
+		return
+	}
+
+	// played is how far into the recording the playback is
+	played := lines[start].At
+
+	for _, l := range lines[start:] {
+		if !r.wait(ctx, &played, l.At) {
+			return
+		}
+		if !send(pkg.Result[string]{V: l.Text}) {
+			return
+		}
+	}
+}
+
+// wait waits until the playback reaches at, for as long as it's paused
+func (r *Replay) wait(ctx context.Context, played *time.Time, at time.Time) bool {
+	for {
+		r.mu.Lock()
+		paused, changed := r.paused, r.changed
+		r.mu.Unlock()
+
+		if paused {
+			select {
+			case <-changed:
+				continue
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		remaining := at.Sub(*played)
+		if r.speed <= 0 || remaining <= 0 {
+			*played = at
+			return true
+		}
+
+		waited := time.Now()
+		timer := time.NewTimer(time.Duration(float64(remaining) / r.speed))
+
+		select {
+		case <-timer.C:
+			*played = at
+			return true
+		case <-changed:
+			timer.Stop()
+			*played = played.Add(time.Duration(float64(time.Since(waited)) * r.speed))
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// a session file is gzipped JSON, one record per line: a header, then
+// stream records as sources are opened and line records as their lines
+// arrive. streams are numbered from 1 in the order they were opened.
+const version = 1
+
+type header struct {
+	Version int       `json:"v"`
+	Started time.Time `json:"started"`
+}
+
+type record struct {
+	// Stream is the stream a record belongs to
+	Stream int `json:"s"`
+	// Meta is set on the record that opens a stream
+	Meta *pkg.LogMeta `json:"meta,omitempty"`
+	// Offset is when a line arrived, in milliseconds since the session
+	// started
+	Offset int64  `json:"t,omitempty"`
+	Line   string `json:"l,omitempty"`
+}
+
+// Line is a recorded line and when it arrived
+type Line struct {
+	At   time.Time
+	Text string
+}
+
+// Stream is everything a source sent during a session
+type Stream struct {
+	ID    int
+	Meta  pkg.LogMeta
+	Lines []Line
+}
+
+// Session is a recorded session, read back for replay
+type Session struct {
+	Path    string
+	Started time.Time
+	Streams []Stream
+}
+
+// Load reads the session file at path. a session that was cut off, e.g.
+// by a crash, is read up to where it ends.
+func Load(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open session: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read session %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(gz)
+
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("read session header: %w", err)
+	}
+	if h.Version != version {
+		return nil, fmt.Errorf("unsupported session version %d", h.Version)
+	}
+
+	s := &Session{Path: path, Started: h.Started}
+	streams := map[int]int{}
+
+	for {
+		var r record
+
+		err := dec.Decode(&r)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read session record: %w", err)
+		}
+
+		if r.Meta != nil {
+			streams[r.Stream] = len(s.Streams)
+			s.Streams = append(s.Streams, Stream{ID: r.Stream, Meta: *r.Meta})
+			continue
+		}
+
+		i, ok := streams[r.Stream]
+		if !ok {
+			return nil, fmt.Errorf("read session: line of unknown stream %d", r.Stream)
+		}
+
+		s.Streams[i].Lines = append(s.Streams[i].Lines, Line{
+			At:   h.Started.Add(time.Duration(r.Offset) * time.Millisecond),
+			Text: r.Line,
+		})
+	}
+
+	return s, nil
+}
+
+// Stream finds a stream by its ID
+func (s *Session) Stream(id int) (Stream, bool) {
+	if s == nil {
+		return Stream{}, false
+	}
+
+	for _, stream := range s.Streams {
+		if stream.ID == id {
+			return stream, true
+		}
+	}
+
+	return Stream{}, false
+}
@@ -0,0 +1,228 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/k8s"
+)
+
+// ArchivedRun is a finished job of a cron job whose container logs were
+// saved, so they outlive the job's pods
+type ArchivedRun struct {
+	Cluster    string          `json:"cluster"`
+	CronJob    string          `json:"cronJob"`
+	Job        k8s.Job         `json:"job"`
+	Containers []k8s.Container `json:"containers"`
+	Archived   time.Time       `json:"archived"`
+}
+
+// archived runs are kept per cluster and namespace: a file per run, and a
+// log file per container of its pods
+func archiveDir(cluster string, namespace string) string {
+	return filepath.Join("archive", url.PathEscape(cluster), url.PathEscape(namespace))
+}
+
+func runFile(cluster string, namespace string, job string) string {
+	return filepath.Join(archiveDir(cluster, namespace), "runs", url.PathEscape(job)+".json")
+}
+
+func logFile(cluster string, c k8s.Container) string {
+	return filepath.Join(
+		archiveDir(cluster, c.Namespace),
+		"logs",
+		url.PathEscape(c.Pod),
+		url.PathEscape(c.Name)+".log",
+	)
+}
+
+// SaveArchivedRun saves a run and the lines of its containers. the run is
+// saved last, so a run that's listed always has its logs.
+func SaveArchivedRun(run ArchivedRun, logs map[k8s.Container][]string) error {
+	for _, c := range run.Containers {
+		data := []byte{}
+		if lines := logs[c]; len(lines) > 0 {
+			data = []byte(strings.Join(lines, "\n") + "\n")
+		}
+
+		if err := writeFile(logFile(run.Cluster, c), data); err != nil {
+			return fmt.Errorf("archive logs of %s/%s: %w", c.Pod, c.Name, err)
+		}
+	}
+
+	return writeJSON(runFile(run.Cluster, run.Job.Namespace, run.Job.Name), run)
+}
+
+// LoadArchivedRun loads the archived run of a job, and reports whether
+// there's one
+func LoadArchivedRun(cluster string, namespace string, job string) (ArchivedRun, bool, error) {
+	var run ArchivedRun
+
+	if err := readJSON(runFile(cluster, namespace, job), &run); err != nil {
+		return ArchivedRun{}, false, err
+	}
+
+	return run, run.Job.Name != "", nil
+}
+
+// LoadArchivedRuns returns the archived runs of a cron job, latest first
+func LoadArchivedRuns(cluster string, namespace string, cronJob string) ([]ArchivedRun, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	runsDir := filepath.Join(archiveDir(cluster, namespace), "runs")
+
+	entries, err := os.ReadDir(filepath.Join(dir, runsDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list archived runs: %w", err)
+	}
+
+	runs := []ArchivedRun{}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+
+		var run ArchivedRun
+
+		if err := readJSON(filepath.Join(runsDir, e.Name()), &run); err != nil {
+			return nil, err
+		}
+
+		if run.CronJob == cronJob {
+			runs = append(runs, run)
+		}
+	}
+
+	slices.SortFunc(runs, func(a, b ArchivedRun) int {
+		return b.Job.StartTime.Compare(a.Job.StartTime)
+	})
+
+	return runs, nil
+}
+
+// LoadArchivedLogs returns the archived lines of a container
+func LoadArchivedLogs(cluster string, container k8s.Container) ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	name := logFile(cluster, container)
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(text, "\n"), nil
+}
+
+// PruneArchivedRuns removes the runs of a cron job past the latest maxRuns,
+// and the ones that started more than maxAge before now. zero keeps them
+// all. it returns the runs that were removed.
+func PruneArchivedRuns(
+	cluster string,
+	namespace string,
+	cronJob string,
+	maxRuns int,
+	maxAge time.Duration,
+	now time.Time,
+) (
+	[]ArchivedRun,
+	error,
+) {
+	runs, err := LoadArchivedRuns(cluster, namespace, cronJob)
+	if err != nil {
+		return nil, err
+	}
+
+	pruned := []ArchivedRun{}
+
+	for i, run := range runs {
+		if retained(i, run.Job.StartTime, maxRuns, maxAge, now) {
+			continue
+		}
+
+		if err := removeArchivedRun(run); err != nil {
+			return pruned, err
+		}
+
+		pruned = append(pruned, run)
+	}
+
+	return pruned, nil
+}
+
+// Retained returns the jobs of a cron job that PruneArchivedRuns would keep
+// if they were all archived, latest first
+func Retained(jobs []k8s.Job, maxRuns int, maxAge time.Duration, now time.Time) []k8s.Job {
+	jobs = slices.Clone(jobs)
+	slices.SortFunc(jobs, func(a, b k8s.Job) int {
+		return b.StartTime.Compare(a.StartTime)
+	})
+
+	kept := []k8s.Job{}
+
+	for i, job := range jobs {
+		if retained(i, job.StartTime, maxRuns, maxAge, now) {
+			kept = append(kept, job)
+		}
+	}
+
+	return kept
+}
+
+// retained reports whether the i-th latest run, which started at start, is
+// within maxRuns and maxAge
+func retained(i int, start time.Time, maxRuns int, maxAge time.Duration, now time.Time) bool {
+	tooMany := maxRuns > 0 && i >= maxRuns
+	tooOld := maxAge > 0 && now.Sub(start) > maxAge
+
+	return !tooMany && !tooOld
+}
+
+// removeArchivedRun removes the run first, so a run is never listed without
+// its logs
+func removeArchivedRun(run ArchivedRun) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	name := runFile(run.Cluster, run.Job.Namespace, run.Job.Name)
+
+	if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove %s: %w", name, err)
+	}
+
+	for _, c := range run.Containers {
+		logs := logFile(run.Cluster, c)
+
+		if err := os.Remove(filepath.Join(dir, logs)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove %s: %w", logs, err)
+		}
+
+		// the pod's dir goes with its last container
+		_ = os.Remove(filepath.Join(dir, filepath.Dir(logs)))
+	}
+
+	return nil
+}
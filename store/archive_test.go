@@ -0,0 +1,154 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshuasprow/log-viewer/k8s"
+)
+
+func TestPruneArchivedRuns(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		maxRuns int
+		maxAge  time.Duration
+		kept    []string
+		pruned  []string
+	}{
+		{
+			name: "no limits",
+			kept: []string{"job-4", "job-3", "job-2", "job-1"},
+		},
+		{
+			name:    "max runs",
+			maxRuns: 2,
+			kept:    []string{"job-4", "job-3"},
+			pruned:  []string{"job-2", "job-1"},
+		},
+		{
+			name:   "max age",
+			maxAge: 60 * time.Hour,
+			kept:   []string{"job-4", "job-3"},
+			pruned: []string{"job-2", "job-1"},
+		},
+		{
+			name:    "max runs and age",
+			maxRuns: 3,
+			maxAge:  84 * time.Hour,
+			kept:    []string{"job-4", "job-3", "job-2"},
+			pruned:  []string{"job-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+			runs := map[string]ArchivedRun{}
+
+			// a run a day, job-4 started a day before now, job-1 four days
+			for i := 1; i <= 4; i++ {
+				run := testRun(t, i, now.Add(-time.Duration(5-i)*24*time.Hour))
+				runs[run.Job.Name] = run
+			}
+
+			pruned, err := PruneArchivedRuns("test", "default", "backup", tt.maxRuns, tt.maxAge, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := jobNames(pruned); !reflect.DeepEqual(got, tt.pruned) {
+				t.Errorf("pruned %v, want %v", got, tt.pruned)
+			}
+
+			kept, err := LoadArchivedRuns("test", "default", "backup")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := jobNames(kept); !reflect.DeepEqual(got, tt.kept) {
+				t.Errorf("kept %v, want %v", got, tt.kept)
+			}
+
+			for _, name := range tt.pruned {
+				if _, err := LoadArchivedLogs("test", runs[name].Containers[0]); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("logs of %s: got error %v, want %v", name, err, os.ErrNotExist)
+				}
+			}
+			for _, name := range tt.kept {
+				if _, err := LoadArchivedLogs("test", runs[name].Containers[0]); err != nil {
+					t.Errorf("logs of %s: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRetained(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	jobs := []k8s.Job{
+		{Name: "job-2", StartTime: now.Add(-2 * time.Hour)},
+		{Name: "job-1", StartTime: now.Add(-3 * time.Hour)},
+		{Name: "job-3", StartTime: now.Add(-1 * time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		maxRuns int
+		maxAge  time.Duration
+		want    []string
+	}{
+		{name: "no limits", want: []string{"job-3", "job-2", "job-1"}},
+		{name: "max runs", maxRuns: 1, want: []string{"job-3"}},
+		{name: "max age", maxAge: 150 * time.Minute, want: []string{"job-3", "job-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, job := range Retained(jobs, tt.maxRuns, tt.maxAge, now) {
+				got = append(got, job.Name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Retained() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testRun(t *testing.T, i int, start time.Time) ArchivedRun {
+	t.Helper()
+
+	name := fmt.Sprintf("job-%d", i)
+	c := k8s.Container{Namespace: "default", Pod: name + "-abcde", Name: "main"}
+
+	run := ArchivedRun{
+		Cluster:    "test",
+		CronJob:    "backup",
+		Job:        k8s.Job{Namespace: "default", Name: name, StartTime: start, Finished: true},
+		Containers: []k8s.Container{c},
+		Archived:   start,
+	}
+
+	if err := SaveArchivedRun(run, map[k8s.Container][]string{c: {"done"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	return run
+}
+
+func jobNames(runs []ArchivedRun) []string {
+	var names []string
+	for _, run := range runs {
+		names = append(names, run.Job.Name)
+	}
+	return names
+}
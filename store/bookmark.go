@@ -0,0 +1,131 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Bookmark marks a log line, optionally with a note. Time is when the line
+// was logged and is zero when the API didn't say.
+type Bookmark struct {
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Time      time.Time `json:"time"`
+	Line      string    `json:"line"`
+	Note      string    `json:"note,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// Marks reports whether b is a bookmark of the line
+func (b Bookmark) Marks(pod string, container string, t time.Time, line string) bool {
+	return b.Pod == pod &&
+		b.Container == container &&
+		b.Time.Equal(t) &&
+		b.Line == line
+}
+
+func (b Bookmark) same(o Bookmark) bool {
+	return b.Cluster == o.Cluster &&
+		b.Namespace == o.Namespace &&
+		o.Marks(b.Pod, b.Container, b.Time, b.Line)
+}
+
+// bookmarks are kept in a file per cluster and namespace
+func bookmarksDir(cluster string) string {
+	return filepath.Join("bookmarks", url.PathEscape(cluster))
+}
+
+func bookmarksFile(cluster string, namespace string) string {
+	return filepath.Join(bookmarksDir(cluster), url.PathEscape(namespace)+".json")
+}
+
+// LoadBookmarks returns the bookmarks of a namespace in the order they were
+// logged. An empty namespace loads every namespace of the cluster.
+func LoadBookmarks(cluster string, namespace string) ([]Bookmark, error) {
+	files := []string{bookmarksFile(cluster, namespace)}
+
+	if namespace == "" {
+		var err error
+		if files, err = bookmarkFiles(cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	all := []Bookmark{}
+
+	for _, file := range files {
+		bookmarks := []Bookmark{}
+
+		if err := readJSON(file, &bookmarks); err != nil {
+			return nil, err
+		}
+
+		all = append(all, bookmarks...)
+	}
+
+	slices.SortStableFunc(all, func(a, b Bookmark) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return all, nil
+}
+
+func bookmarkFiles(cluster string) ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, bookmarksDir(cluster)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list bookmarks: %w", err)
+	}
+
+	files := []string{}
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, filepath.Join(bookmarksDir(cluster), e.Name()))
+		}
+	}
+
+	return files, nil
+}
+
+// AddBookmark saves b, replacing the note of an existing bookmark of the
+// same line
+func AddBookmark(b Bookmark) error {
+	file := bookmarksFile(b.Cluster, b.Namespace)
+	bookmarks := []Bookmark{}
+
+	if err := readJSON(file, &bookmarks); err != nil {
+		return err
+	}
+
+	bookmarks = slices.DeleteFunc(bookmarks, b.same)
+	bookmarks = append(bookmarks, b)
+
+	return writeJSON(file, bookmarks)
+}
+
+func RemoveBookmark(b Bookmark) error {
+	file := bookmarksFile(b.Cluster, b.Namespace)
+	bookmarks := []Bookmark{}
+
+	if err := readJSON(file, &bookmarks); err != nil {
+		return err
+	}
+
+	return writeJSON(file, slices.DeleteFunc(bookmarks, b.same))
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Dir is where the viewer keeps state between runs
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+
+	if dir == "" {
+		homedir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("get user home dir: %w", err)
+		}
+
+		dir = filepath.Join(homedir, ".local", "state")
+	}
+
+	return filepath.Join(dir, "log-viewer"), nil
+}
+
+func readJSON(name string, v any) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func writeJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+
+	return writeFile(name, data)
+}
+
+// writeFile replaces the file atomically so a crash never leaves it
+// half-written
+func writeFile(name string, data []byte) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, name)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", name, err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// History is a most-recent-first list of values per key, e.g. the
+// namespaces entered per kube context
+type History struct {
+	name string
+	max  int
+}
+
+var (
+	NamespaceHistory = History{name: "namespaces.json", max: 20}
+	// SelectorHistory keeps label and field selectors under "label" and
+	// "field"
+	SelectorHistory = History{name: "selectors.json", max: 20}
+)
+
+func (h History) Load(key string) ([]string, error) {
+	all := map[string][]string{}
+
+	if err := readJSON(h.name, &all); err != nil {
+		return nil, err
+	}
+
+	return all[key], nil
+}
+
+func (h History) Add(key string, value string) error {
+	all := map[string][]string{}
+
+	if err := readJSON(h.name, &all); err != nil {
+		return err
+	}
+
+	values := slices.DeleteFunc(all[key], func(v string) bool { return v == value })
+	values = append([]string{value}, values...)
+
+	if len(values) > h.max {
+		values = values[:h.max]
+	}
+
+	all[key] = values
+
+	return writeJSON(h.name, all)
+}
@@ -9,13 +9,19 @@ func (a Api) FilterValue() string {
 }
 
 const (
-	ContainersApi Api = "containers"
-	CronJobsApi   Api = "cron jobs"
+	ContainersApi  Api = "containers"
+	CronJobsApi    Api = "cron jobs"
+	PermissionsApi Api = "permissions"
+	BookmarksApi   Api = "bookmarks"
+	FilesApi       Api = "local files"
 )
 
 func GetApis() []list.Item {
 	return []list.Item{
 		ContainersApi,
 		CronJobsApi,
+		PermissionsApi,
+		BookmarksApi,
+		FilesApi,
 	}
 }
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/joshuasprow/log-viewer/store"
+)
+
+type Bookmark struct {
+	store.Bookmark
+}
+
+func (b Bookmark) Title() string {
+	return "★ " + Log{Text: b.Line}.Title()
+}
+
+func (b Bookmark) Description() string {
+	desc := fmt.Sprintf("%s %s/%s", b.loggedAt(), b.Pod, b.Container)
+	if b.Note != "" {
+		desc += " · " + b.Note
+	}
+	return desc
+}
+
+func (b Bookmark) FilterValue() string {
+	return strings.Join([]string{b.Pod, b.Container, b.Line, b.Note}, " ")
+}
+
+func (b Bookmark) loggedAt() string {
+	if b.Time.IsZero() {
+		return "unknown time"
+	}
+	return FormatTime(b.Time)
+}
+
+func WrapBookmarks(bookmarks []store.Bookmark) []list.Item {
+	wrapped := make([]list.Item, len(bookmarks))
+	for i, b := range bookmarks {
+		wrapped[i] = Bookmark{b}
+	}
+	return wrapped
+}
+
+// RenderTimeline writes bookmarks as a Markdown incident timeline, oldest
+// first. times are UTC so the timeline reads the same everywhere.
+func RenderTimeline(
+	cluster string,
+	namespace string,
+	bookmarks []store.Bookmark,
+	exported time.Time,
+) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "# Incident timeline: %s / %s\n\n", cluster, NamespaceTitle(namespace))
+	fmt.Fprintf(b, "Exported %s\n", exported.UTC().Format(time.RFC3339))
+
+	if len(bookmarks) == 0 {
+		b.WriteString("\nNo bookmarks.\n")
+	}
+
+	for _, bm := range bookmarks {
+		when := "unknown time"
+		if !bm.Time.IsZero() {
+			when = bm.Time.UTC().Format(time.RFC3339Nano)
+		}
+
+		fmt.Fprintf(b, "\n## %s · %s/%s", when, bm.Pod, bm.Container)
+		if namespace == "" {
+			fmt.Fprintf(b, " (%s)", bm.Namespace)
+		}
+		b.WriteString("\n\n")
+
+		for _, line := range strings.Split(bm.Line, "\n") {
+			fmt.Fprintf(b, "> %s\n", line)
+		}
+
+		if bm.Note != "" {
+			fmt.Fprintf(b, "\n%s\n", bm.Note)
+		}
+	}
+
+	return b.String()
+}
@@ -9,16 +9,34 @@ import (
 
 type Container struct {
 	k8s.Container
+	// NamespaceWidth pads the namespace into a column when listing
+	// containers from all namespaces. Zero leaves it out.
+	NamespaceWidth int
+}
+
+func (c Container) Title() string {
+	if c.NamespaceWidth == 0 {
+		return c.FilterValue()
+	}
+	return fmt.Sprintf("%-*s  %s.%s", c.NamespaceWidth, c.Namespace, c.Pod, c.Name)
 }
 
 func (c Container) FilterValue() string {
 	return fmt.Sprintf("%s.%s.%s", c.Namespace, c.Pod, c.Name)
 }
 
-func WrapContainers(containers []k8s.Container) []list.Item {
+func WrapContainers(containers []k8s.Container, showNamespace bool) []list.Item {
+	width := 0
+
+	if showNamespace {
+		for _, c := range containers {
+			width = max(width, len(c.Namespace))
+		}
+	}
+
 	wrapped := make([]list.Item, len(containers))
 	for i, c := range containers {
-		wrapped[i] = Container{c}
+		wrapped[i] = Container{Container: c, NamespaceWidth: width}
 	}
 	return wrapped
 }
@@ -9,27 +9,41 @@ import (
 
 type CronJob struct {
 	k8s.CronJob
+	// NamespaceWidth pads the namespace into a column when listing cron jobs
+	// from all namespaces. Zero leaves it out.
+	NamespaceWidth int
 }
 
 func (c CronJob) Title() string {
-	return fmt.Sprintf("%s.%s", c.Namespace, c.Name)
+	if c.NamespaceWidth == 0 {
+		return c.FilterValue()
+	}
+	return fmt.Sprintf("%-*s  %s", c.NamespaceWidth, c.Namespace, c.Name)
 }
 
 func (c CronJob) Description() string {
 	return fmt.Sprintf(
 		"last_scheduled=%s",
-		c.LastScheduleTime.Format("2006-01-02T15:04:05"),
+		FormatTime(c.LastScheduleTime),
 	)
 }
 
 func (c CronJob) FilterValue() string {
-	return c.Title()
+	return fmt.Sprintf("%s.%s", c.Namespace, c.Name)
 }
 
-func WrapCronJobs(cronJobs []k8s.CronJob) []list.Item {
+func WrapCronJobs(cronJobs []k8s.CronJob, showNamespace bool) []list.Item {
+	width := 0
+
+	if showNamespace {
+		for _, c := range cronJobs {
+			width = max(width, len(c.Namespace))
+		}
+	}
+
 	wrapped := make([]list.Item, len(cronJobs))
 	for i, c := range cronJobs {
-		wrapped[i] = CronJob{c}
+		wrapped[i] = CronJob{CronJob: c, NamespaceWidth: width}
 	}
 	return wrapped
 }
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/diff"
+)
+
+// DiffLine is a line of the diff of two runs' logs
+type DiffLine struct {
+	diff.Line
+	// width fits the line numbers of the longer side
+	width int
+}
+
+// Title is the line without its timestamp, since the times of the two runs
+// never match anyway
+func (l DiffLine) Title() string {
+	text := ParseLog(l.Text).Text
+
+	switch l.Op {
+	case diff.Added:
+		return lipgloss.NewStyle().Foreground(ActiveTheme.Added).Render(text)
+	case diff.Removed:
+		return lipgloss.NewStyle().Foreground(ActiveTheme.Removed).Render(text)
+	default:
+		return text
+	}
+}
+
+// Gutter shows the line's numbers on the left and right side, then + for
+// added lines and - for removed ones
+func (l DiffLine) Gutter() string {
+	number := func(n int) string {
+		if n == 0 {
+			return padLeft("", l.width)
+		}
+		return padLeft(strconv.Itoa(n), l.width)
+	}
+
+	muted := lipgloss.NewStyle().Foreground(ActiveTheme.Muted)
+	numbers := muted.Render(number(l.Left) + " " + number(l.Right))
+
+	switch l.Op {
+	case diff.Added:
+		return numbers + lipgloss.NewStyle().Foreground(ActiveTheme.Added).Render(" + ")
+	case diff.Removed:
+		return numbers + lipgloss.NewStyle().Foreground(ActiveTheme.Removed).Render(" - ")
+	default:
+		return numbers + "   "
+	}
+}
+
+func (l DiffLine) FilterValue() string {
+	return ParseLog(l.Text).Text
+}
+
+func WrapDiff(lines []diff.Line) []list.Item {
+	last := 0
+	for _, l := range lines {
+		last = max(last, l.Left, l.Right)
+	}
+	width := len(strconv.Itoa(last))
+
+	wrapped := make([]list.Item, len(lines))
+	for i, l := range lines {
+		wrapped[i] = DiffLine{Line: l, width: width}
+	}
+	return wrapped
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/joshuasprow/log-viewer/local"
+)
+
+type File struct {
+	local.Source
+}
+
+func (f File) Title() string {
+	return f.Path
+}
+
+func (f File) Description() string {
+	desc := fmt.Sprintf("%s modified %s", formatSize(f.Size), FormatTime(f.ModTime))
+
+	if n := len(f.Rotated); n > 0 {
+		desc += fmt.Sprintf(" · %d rotated", n)
+	}
+	if f.Compressed() {
+		desc += " · gzip"
+	}
+
+	return desc
+}
+
+func (f File) FilterValue() string {
+	return f.Path
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func WrapFiles(sources []local.Source) []list.Item {
+	wrapped := make([]list.Item, len(sources))
+	for i, s := range sources {
+		wrapped[i] = File{s}
+	}
+	return wrapped
+}
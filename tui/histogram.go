@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stacked are the levels a histogram bar is stacked by, from the bottom
+var stacked = []Level{LevelError, LevelWarn, LevelInfo, LevelDebug, LevelUnknown}
+
+// Histogram counts log entries per level in buckets of equal time, from the
+// first entry's time to the last's
+type Histogram struct {
+	Start   time.Time
+	End     time.Time
+	Buckets [][LevelError + 1]int
+}
+
+// NewHistogram buckets the entries that have a time. it reports false when
+// none do, e.g. for lines of a file without timestamps.
+func NewHistogram(logs []Log, buckets int) (Histogram, bool) {
+	h := Histogram{}
+
+	for _, l := range logs {
+		if l.Time.IsZero() {
+			continue
+		}
+		if h.Start.IsZero() || l.Time.Before(h.Start) {
+			h.Start = l.Time
+		}
+		if l.Time.After(h.End) {
+			h.End = l.Time
+		}
+	}
+
+	if h.Start.IsZero() || buckets <= 0 {
+		return Histogram{}, false
+	}
+
+	h.Buckets = make([][LevelError + 1]int, buckets)
+
+	for _, l := range logs {
+		if !l.Time.IsZero() {
+			h.Buckets[h.Bucket(l.Time)][l.Level]++
+		}
+	}
+
+	return h, true
+}
+
+// Bucket is the index of the bucket t falls in
+func (h Histogram) Bucket(t time.Time) int {
+	span := h.End.Sub(h.Start)
+	if span <= 0 {
+		return 0
+	}
+
+	i := int(float64(t.Sub(h.Start)) / float64(span) * float64(len(h.Buckets)))
+
+	return min(max(i, 0), len(h.Buckets)-1)
+}
+
+// BucketStart is the earliest time in bucket i
+func (h Histogram) BucketStart(i int) time.Time {
+	span := h.End.Sub(h.Start)
+	return h.Start.Add(time.Duration(float64(span) * float64(i) / float64(len(h.Buckets))))
+}
+
+// Total is the number of entries in bucket i
+func (h Histogram) Total(i int) int {
+	total := 0
+	for _, n := range h.Buckets[i] {
+		total += n
+	}
+	return total
+}
+
+// Nearest finds the closest bucket with entries from i in direction step,
+// not counting i itself. it returns -1 when there's none.
+func (h Histogram) Nearest(i int, step int) int {
+	for j := i + step; j >= 0 && j < len(h.Buckets); j += step {
+		if h.Total(j) > 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+// Render draws the bars height rows high, scaled to the fullest bucket, over
+// an axis with the first and last times and a marker at the cursor bucket.
+// a cursor of -1 draws no marker.
+func (h Histogram) Render(height int, cursor int) string {
+	fullest := 0
+	for i := range h.Buckets {
+		fullest = max(fullest, h.Total(i))
+	}
+
+	columns := make([][]Level, len(h.Buckets))
+	for i := range h.Buckets {
+		columns[i] = h.stack(i, height, fullest)
+	}
+
+	// a cell of each level is rendered once, not for every cell
+	bars := map[Level]string{}
+	for l := LevelUnknown; l <= LevelError; l++ {
+		bars[l] = lipgloss.NewStyle().Foreground(levelColor(l)).Render("█")
+	}
+
+	rows := make([]string, 0, height+1)
+
+	for row := height - 1; row >= 0; row-- {
+		var b strings.Builder
+
+		for _, cells := range columns {
+			if row >= len(cells) {
+				b.WriteString(" ")
+				continue
+			}
+			b.WriteString(bars[cells[row]])
+		}
+
+		rows = append(rows, b.String())
+	}
+
+	return strings.Join(append(rows, h.axis(cursor)), "\n")
+}
+
+// stack splits the cells of bucket i's bar between its levels. every level
+// in the bucket gets a cell, the most severe first, so a single error in a
+// busy bucket still shows.
+func (h Histogram) stack(i int, height int, fullest int) []Level {
+	total := h.Total(i)
+	if total == 0 {
+		return nil
+	}
+
+	cells := max((total*height+fullest/2)/fullest, 1)
+
+	counts := h.Buckets[i]
+	alloc := map[Level]int{}
+	left := cells
+
+	for _, l := range stacked {
+		if counts[l] > 0 && left > 0 {
+			alloc[l]++
+			left--
+		}
+	}
+
+	// the rest goes to the levels furthest below their share
+	for ; left > 0; left-- {
+		best := stacked[0]
+		for _, l := range stacked {
+			if counts[l]*cells-alloc[l]*total > counts[best]*cells-alloc[best]*total {
+				best = l
+			}
+		}
+		alloc[best]++
+	}
+
+	levels := make([]Level, 0, cells)
+	for _, l := range stacked {
+		for range alloc[l] {
+			levels = append(levels, l)
+		}
+	}
+
+	return levels
+}
+
+func (h Histogram) axis(cursor int) string {
+	width := len(h.Buckets)
+	axis := []rune(strings.Repeat(" ", width))
+
+	put := func(at int, s string) {
+		for i, r := range []rune(s) {
+			if at+i >= 0 && at+i < width {
+				axis[at+i] = r
+			}
+		}
+	}
+
+	start := FormatTime(h.Start)
+	end := FormatTime(h.End)
+
+	put(0, start)
+	put(width-len([]rune(end)), end)
+
+	muted := lipgloss.NewStyle().Foreground(ActiveTheme.Muted)
+
+	if cursor < 0 {
+		return muted.Render(string(axis))
+	}
+
+	marker := "▲ " + FormatTime(h.BucketStart(cursor))
+	at := cursor
+	if cursor+len([]rune(marker)) > width {
+		marker = FormatTime(h.BucketStart(cursor)) + " ▲"
+		at = cursor - len([]rune(marker)) + 1
+	}
+
+	// the marker covers the labels it overlaps
+	before := string(axis[:max(at, 0)])
+	after := string(axis[min(max(at+len([]rune(marker)), 0), width):])
+
+	selected := lipgloss.NewStyle().Foreground(ActiveTheme.Selected)
+
+	return muted.Render(before) + selected.Render(marker) + muted.Render(after)
+}
+
+func levelColor(l Level) lipgloss.TerminalColor {
+	switch l {
+	case LevelError:
+		return ActiveTheme.Error
+	case LevelWarn:
+		return ActiveTheme.Warn
+	case LevelInfo:
+		return ActiveTheme.Info
+	default:
+		return ActiveTheme.Muted
+	}
+}
@@ -9,6 +9,9 @@ import (
 
 type Job struct {
 	k8s.Job
+	// Archived marks a run whose logs are read from the archive, since its
+	// job was cleaned up
+	Archived bool
 }
 
 func (j Job) Title() string {
@@ -16,13 +19,19 @@ func (j Job) Title() string {
 	if j.Failed > 0 {
 		icon = "🚫"
 	}
-	return fmt.Sprintf("%s %s.%s", icon, j.Namespace, j.Name)
+
+	title := fmt.Sprintf("%s %s.%s", icon, j.Namespace, j.Name)
+	if j.Archived {
+		title += " (archived)"
+	}
+
+	return title
 }
 
 func (j Job) Description() string {
 	return fmt.Sprintf(
 		"start_time=%s failed=%d succeeded=%d",
-		j.StartTime.Format("2006-01-02T15:04:05"),
+		FormatTime(j.StartTime),
 		j.Failed,
 		j.Succeeded,
 	)
@@ -32,10 +41,14 @@ func (j Job) FilterValue() string {
 	return j.Title()
 }
 
-func WrapJobs(jobs []k8s.Job) []list.Item {
-	wrapped := make([]list.Item, len(jobs))
-	for i, j := range jobs {
-		wrapped[i] = Job{j}
+// WrapJobs lists the live jobs, then the archived ones
+func WrapJobs(jobs []k8s.Job, archived []k8s.Job) []list.Item {
+	wrapped := make([]list.Item, 0, len(jobs)+len(archived))
+	for _, j := range jobs {
+		wrapped = append(wrapped, Job{Job: j})
+	}
+	for _, j := range archived {
+		wrapped = append(wrapped, Job{Job: j, Archived: true})
 	}
 	return wrapped
 }
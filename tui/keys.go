@@ -0,0 +1,380 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PrevPage key.Binding
+	NextPage key.Binding
+	Start    key.Binding
+	End      key.Binding
+
+	Filter       key.Binding
+	ClearFilter  key.Binding
+	AcceptFilter key.Binding
+	CancelFilter key.Binding
+
+	Select key.Binding
+	Back   key.Binding
+
+	Help      key.Binding
+	Quit      key.Binding
+	ForceQuit key.Binding
+
+	Debug key.Binding
+
+	Retry   key.Binding
+	Details key.Binding
+
+	Selector key.Binding
+
+	NextField   key.Binding
+	PrevField   key.Binding
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+
+	Split       key.Binding
+	FocusPane   key.Binding
+	SplitLayout key.Binding
+	SyncScroll  key.Binding
+
+	NewTab    key.Binding
+	CloseTab  key.Binding
+	RenameTab key.Binding
+	NextTab   key.Binding
+	PrevTab   key.Binding
+
+	Bookmark     key.Binding
+	NextBookmark key.Binding
+	PrevBookmark key.Binding
+	Export       key.Binding
+
+	TimeGutter key.Binding
+	GoToTime   key.Binding
+
+	Expand key.Binding
+
+	Copy        key.Binding
+	FilterField key.Binding
+
+	Pause key.Binding
+
+	Timeline     key.Binding
+	TimelinePrev key.Binding
+	TimelineNext key.Binding
+}
+
+var Keys = DefaultKeyMap()
+
+// keyScopes lists actions that are active at the same time, so must not
+// share keys. e.g. back is disabled while a filter is applied, so it can
+// share a key with clear_filter.
+var keyScopes = map[string][]string{
+	"browsing": {
+		"up", "down", "prev_page", "next_page", "start", "end",
+		"filter", "select", "back", "help", "quit", "force_quit", "debug",
+		"selector", "split",
+		"bookmark", "next_bookmark", "prev_bookmark", "export", "time_gutter",
+		"go_to_time", "expand", "pause", "timeline", "timeline_prev", "timeline_next",
+		"new_tab", "close_tab", "rename_tab", "next_tab", "prev_tab",
+	},
+	"filtered": {
+		"up", "down", "prev_page", "next_page", "start", "end",
+		"filter", "clear_filter", "select", "help", "quit", "force_quit", "debug",
+		"selector", "split",
+		"bookmark", "next_bookmark", "prev_bookmark", "export", "time_gutter",
+		"go_to_time", "expand", "pause", "timeline", "timeline_prev", "timeline_next",
+		"new_tab", "close_tab", "rename_tab", "next_tab", "prev_tab",
+	},
+	"filtering": {
+		"accept_filter", "cancel_filter", "force_quit",
+	},
+	"prompt": {
+		"accept_filter", "cancel_filter", "force_quit",
+		"next_field", "prev_field", "history_prev", "history_next",
+	},
+	"split": {
+		"up", "down", "prev_page", "next_page", "start", "end",
+		"filter", "back", "quit", "force_quit", "debug",
+		"focus_pane", "split_layout", "sync_scroll", "time_gutter",
+		"new_tab", "close_tab", "rename_tab", "next_tab", "prev_tab",
+	},
+	"detail": {
+		"up", "down", "prev_page", "next_page", "start", "end",
+		"select", "expand", "copy", "filter_field", "back", "quit", "force_quit",
+	},
+	"error": {
+		"retry", "details", "back", "quit", "force_quit", "debug",
+		"new_tab", "close_tab", "rename_tab", "next_tab", "prev_tab",
+	},
+}
+
+var keyPresets = map[string]func() KeyMap{
+	"default": DefaultKeyMap,
+	"vim":     VimKeyMap,
+	"emacs":   EmacsKeyMap,
+}
+
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(helpKeys(keys), desc),
+	)
+}
+
+func helpKeys(keys []string) string {
+	if len(keys) > 2 {
+		keys = keys[:2]
+	}
+
+	help := make([]string, len(keys))
+
+	for i, k := range keys {
+		switch k {
+		case "up":
+			help[i] = "↑"
+		case "down":
+			help[i] = "↓"
+		case "left":
+			help[i] = "←"
+		case "right":
+			help[i] = "→"
+		default:
+			help[i] = k
+		}
+	}
+
+	return strings.Join(help, "/")
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:       newBinding("up", "up", "k"),
+		Down:     newBinding("down", "down", "j"),
+		PrevPage: newBinding("prev page", "left", "h", "pgup", "b", "u"),
+		NextPage: newBinding("next page", "right", "l", "pgdown", "f", "d"),
+		Start:    newBinding("go to start", "home", "g"),
+		End:      newBinding("go to end", "end", "G"),
+
+		Filter:       newBinding("filter", "/"),
+		ClearFilter:  newBinding("clear filter", "esc"),
+		AcceptFilter: newBinding("apply filter", "enter"),
+		CancelFilter: newBinding("cancel", "esc"),
+
+		Select: newBinding("select", "enter"),
+		Back:   newBinding("previous page", "esc"),
+
+		Help:      newBinding("help", "?"),
+		Quit:      newBinding("quit", "q"),
+		ForceQuit: newBinding("force quit", "ctrl+c"),
+
+		Debug: newBinding("request queue", "D"),
+
+		Retry:   newBinding("retry", "r"),
+		Details: newBinding("details", "d"),
+
+		Selector: newBinding("selectors", "s"),
+
+		NextField:   newBinding("next field", "tab"),
+		PrevField:   newBinding("prev field", "shift+tab"),
+		HistoryPrev: newBinding("older", "up"),
+		HistoryNext: newBinding("newer", "down"),
+
+		Split:       newBinding("compare", "|"),
+		FocusPane:   newBinding("switch pane", "tab"),
+		SplitLayout: newBinding("layout", "L"),
+		SyncScroll:  newBinding("sync scroll", "S"),
+
+		NewTab:    newBinding("new tab", "t"),
+		CloseTab:  newBinding("close tab", "w"),
+		RenameTab: newBinding("rename tab", "R"),
+		NextTab:   newBinding("next tab", "]"),
+		PrevTab:   newBinding("prev tab", "["),
+
+		Bookmark:     newBinding("bookmark", "m"),
+		NextBookmark: newBinding("next bookmark", "n"),
+		PrevBookmark: newBinding("prev bookmark", "N"),
+		Export:       newBinding("export", "e"),
+
+		TimeGutter: newBinding("timestamps", "T"),
+		GoToTime:   newBinding("go to time", "@"),
+
+		Expand: newBinding("expand", "x"),
+
+		Copy:        newBinding("copy value", "y"),
+		FilterField: newBinding("filter on value", "+"),
+
+		Pause: newBinding("pause", "p"),
+
+		Timeline:     newBinding("timeline", "H"),
+		TimelinePrev: newBinding("earlier on timeline", "<"),
+		TimelineNext: newBinding("later on timeline", ">"),
+	}
+}
+
+func VimKeyMap() KeyMap {
+	k := DefaultKeyMap()
+
+	k.PrevPage = newBinding("prev page", "ctrl+b", "ctrl+u", "pgup")
+	k.NextPage = newBinding("next page", "ctrl+f", "ctrl+d", "pgdown")
+	k.Start = newBinding("go to start", "g", "home")
+	k.End = newBinding("go to end", "G", "end")
+	k.Select = newBinding("select", "enter", "l")
+	k.Back = newBinding("previous page", "esc", "h")
+
+	return k
+}
+
+func EmacsKeyMap() KeyMap {
+	k := DefaultKeyMap()
+
+	k.Up = newBinding("up", "ctrl+p", "up")
+	k.Down = newBinding("down", "ctrl+n", "down")
+	k.PrevPage = newBinding("prev page", "alt+v", "pgup")
+	k.NextPage = newBinding("next page", "ctrl+v", "pgdown")
+	k.Start = newBinding("go to start", "alt+<", "home")
+	k.End = newBinding("go to end", "alt+>", "end")
+	k.Filter = newBinding("filter", "ctrl+s", "/")
+	k.ClearFilter = newBinding("clear filter", "ctrl+g", "esc")
+	k.AcceptFilter = newBinding("apply filter", "enter", "ctrl+j")
+	k.CancelFilter = newBinding("cancel", "ctrl+g", "esc")
+	k.Select = newBinding("select", "enter", "ctrl+j")
+	k.Back = newBinding("previous page", "ctrl+g", "esc")
+
+	return k
+}
+
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":            &k.Up,
+		"down":          &k.Down,
+		"prev_page":     &k.PrevPage,
+		"next_page":     &k.NextPage,
+		"start":         &k.Start,
+		"end":           &k.End,
+		"filter":        &k.Filter,
+		"clear_filter":  &k.ClearFilter,
+		"accept_filter": &k.AcceptFilter,
+		"cancel_filter": &k.CancelFilter,
+		"select":        &k.Select,
+		"back":          &k.Back,
+		"help":          &k.Help,
+		"quit":          &k.Quit,
+		"force_quit":    &k.ForceQuit,
+		"debug":         &k.Debug,
+		"retry":         &k.Retry,
+		"details":       &k.Details,
+		"selector":      &k.Selector,
+		"next_field":    &k.NextField,
+		"prev_field":    &k.PrevField,
+		"history_prev":  &k.HistoryPrev,
+		"history_next":  &k.HistoryNext,
+		"split":         &k.Split,
+		"focus_pane":    &k.FocusPane,
+		"split_layout":  &k.SplitLayout,
+		"sync_scroll":   &k.SyncScroll,
+		"new_tab":       &k.NewTab,
+		"close_tab":     &k.CloseTab,
+		"rename_tab":    &k.RenameTab,
+		"next_tab":      &k.NextTab,
+		"prev_tab":      &k.PrevTab,
+		"bookmark":      &k.Bookmark,
+		"next_bookmark": &k.NextBookmark,
+		"prev_bookmark": &k.PrevBookmark,
+		"export":        &k.Export,
+		"time_gutter":   &k.TimeGutter,
+		"go_to_time":    &k.GoToTime,
+		"expand":        &k.Expand,
+		"copy":          &k.Copy,
+		"filter_field":  &k.FilterField,
+		"pause":         &k.Pause,
+		"timeline":      &k.Timeline,
+		"timeline_prev": &k.TimelinePrev,
+		"timeline_next": &k.TimelineNext,
+	}
+}
+
+// NewKeyMap starts from a preset ("default", "vim" or "emacs"; empty means
+// "default") and replaces the keys of every action in bindings.
+func NewKeyMap(preset string, bindings map[string][]string) (KeyMap, error) {
+	if preset == "" {
+		preset = "default"
+	}
+
+	newPreset, ok := keyPresets[preset]
+	if !ok {
+		return KeyMap{}, fmt.Errorf(
+			"unknown preset %q (want one of %s)",
+			preset,
+			strings.Join(sortedKeys(keyPresets), ", "),
+		)
+	}
+
+	k := newPreset()
+	actions := k.actions()
+
+	for action, keys := range bindings {
+		b, ok := actions[action]
+		if !ok {
+			return KeyMap{}, fmt.Errorf(
+				"unknown action %q (want one of %s)",
+				action,
+				strings.Join(sortedKeys(actions), ", "),
+			)
+		}
+
+		if len(keys) == 0 || slices.Contains(keys, "") {
+			return KeyMap{}, fmt.Errorf("%s: keys can't be empty", action)
+		}
+
+		*b = newBinding(b.Help().Desc, keys...)
+	}
+
+	if err := k.validate(); err != nil {
+		return KeyMap{}, err
+	}
+
+	return k, nil
+}
+
+func (k KeyMap) validate() error {
+	actions := k.actions()
+
+	for _, scope := range sortedKeys(keyScopes) {
+		owners := map[string]string{}
+
+		for _, action := range keyScopes[scope] {
+			for _, key := range actions[action].Keys() {
+				if owner, ok := owners[key]; ok {
+					return fmt.Errorf(
+						"%q is bound to both %s and %s",
+						key,
+						owner,
+						action,
+					)
+				}
+
+				owners[key] = action
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
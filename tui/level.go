@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Level is the severity of a log entry, as far as its text tells
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var (
+	// levelField matches a level field of a structured line, e.g.
+	// "level":"error" or lvl=warn, which wins over words in the message
+	levelField = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)"?\s*[:=]\s*"?([a-z]+)`)
+	levelWord  = regexp.MustCompile(`(?i)\b(fatal|panic|critical|error|warn|warning|info|debug|trace)\b`)
+)
+
+// DetectLevel finds the level of a line by its level field, or else the
+// first level word in it
+func DetectLevel(text string) Level {
+	if m := levelField.FindStringSubmatch(text); m != nil {
+		if l := parseLevel(m[1]); l != LevelUnknown {
+			return l
+		}
+	}
+
+	if m := levelWord.FindStringSubmatch(text); m != nil {
+		return parseLevel(m[1])
+	}
+
+	return LevelUnknown
+}
+
+func parseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "fatal", "panic", "critical", "crit", "error", "err":
+		return LevelError
+	case "warn", "warning":
+		return LevelWarn
+	case "info":
+		return LevelInfo
+	case "debug", "trace":
+		return LevelDebug
+	default:
+		return LevelUnknown
+	}
+}
@@ -1,17 +1,193 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/list"
+import (
+	"fmt"
+	"strings"
+	"time"
 
-type Log string
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// Multiline groups the lines of stack traces and other multi-line entries
+var Multiline = pkg.DefaultMultiline()
+
+// Log is a log entry, a single line or the lines grouped into it. Time is
+// only set for lines that were requested with timestamps, Prev is the time
+// of the entry before. Level is detected from the first line.
+type Log struct {
+	Time       time.Time
+	Prev       time.Time
+	Text       string
+	Level      Level
+	Bookmarked bool
+}
+
+// Lines splits the entry into the lines grouped into it
+func (l Log) Lines() []string {
+	return strings.Split(l.Text, "\n")
+}
+
+// Title is the first line of the entry, with the number of lines collapsed
+// under it. errors and warnings are colored, debug lines muted.
+func (l Log) Title() string {
+	first, rest, grouped := strings.Cut(l.Text, "\n")
+
+	title := first
+	if grouped {
+		title += fmt.Sprintf("  (+%d lines)", strings.Count(rest, "\n")+1)
+	}
+
+	switch l.Level {
+	case LevelError, LevelWarn, LevelDebug:
+		title = lipgloss.NewStyle().Foreground(levelColor(l.Level)).Render(title)
+	}
+
+	if l.Bookmarked {
+		return "★ " + title
+	}
+	return title
+}
 
 func (l Log) FilterValue() string {
-	return string(l)
+	return l.Text
 }
 
-func WrapLogs(logs []string) []list.Item {
-	wrapped := make([]list.Item, len(logs))
-	for i, l := range logs {
-		wrapped[i] = Log(l)
+// Gap reports whether the line came long after the one before it
+func (l Log) Gap() bool {
+	return !l.Time.IsZero() &&
+		!l.Prev.IsZero() &&
+		l.Time.Sub(l.Prev) >= GapThreshold
+}
+
+// Gutter shows the time of the line in the TimeGutter mode, followed by a
+// marker for gaps
+func (l Log) Gutter() string {
+	if l.Time.IsZero() {
+		return ""
+	}
+
+	var ts string
+
+	switch TimeGutter {
+	case GutterLocal:
+		ts = FormatTime(l.Time)
+	case GutterUTC:
+		ts = l.Time.UTC().Format(TimestampFormat)
+	case GutterRelative:
+		ts = Ago(l.Time, time.Now())
+	case GutterDelta:
+		if !l.Prev.IsZero() {
+			ts = Delta(l.Time.Sub(l.Prev))
+		}
+	}
+
+	muted := lipgloss.NewStyle().Foreground(ActiveTheme.Muted)
+	gutter := ""
+
+	if TimeGutter != GutterOff {
+		gutter = muted.Render(padRight(ts, gutterWidth())) + " "
 	}
-	return wrapped
+
+	if l.Gap() {
+		return gutter + lipgloss.NewStyle().Foreground(ActiveTheme.Error).Render("┃") + " "
+	}
+
+	return gutter + "  "
+}
+
+func gutterWidth() int {
+	switch TimeGutter {
+	case GutterLocal, GutterUTC:
+		return len(time.Time{}.Format(TimestampFormat))
+	case GutterRelative:
+		return len("59m ago")
+	case GutterDelta:
+		return len("+59.9s")
+	default:
+		return 0
+	}
+}
+
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-len(s), 0))
+}
+
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-len(s), 0)) + s
+}
+
+// ParseLog splits off the RFC3339 timestamp the API prefixes each line with
+// when asked for timestamps. Lines without one are kept as they are.
+func ParseLog(line string) Log {
+	ts, text, ok := strings.Cut(line, " ")
+	if !ok {
+		return Log{Text: line, Level: DetectLevel(line)}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Log{Text: line, Level: DetectLevel(line)}
+	}
+
+	return Log{Time: t, Text: text, Level: DetectLevel(text)}
+}
+
+// ParseLogs parses lines and groups the ones that continue an entry, e.g.
+// the frames of a stack trace. last is the entry logged before lines, if
+// any. when lines continue it, continued is set and the first entry
+// replaces it.
+func ParseLogs(last Log, lines []string) (entries []list.Item, continued bool) {
+	entries = []list.Item{}
+
+	entry := last
+	group := []string{}
+	if last.Text != "" {
+		group = last.Lines()
+	}
+
+	add := func() {
+		if len(group) > 0 {
+			entry.Text = pkg.JoinEntry(group)
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, line := range lines {
+		l := ParseLog(line)
+
+		if Multiline.Continues(group, l.Text) {
+			group = append(group, l.Text)
+			continue
+		}
+		if strings.TrimSpace(l.Text) == "" {
+			continue
+		}
+
+		add()
+
+		l.Prev = entry.Time
+		if l.Prev.IsZero() {
+			l.Prev = entry.Prev
+		}
+
+		entry = l
+		group = []string{l.Text}
+	}
+
+	add()
+
+	if last.Text == "" {
+		return entries, false
+	}
+	if entries[0].(Log).Text == last.Text {
+		return entries[1:], false
+	}
+	return entries, true
+}
+
+func WrapLogs(logs []string) []list.Item {
+	entries, _ := ParseLogs(Log{}, logs)
+	return entries
 }
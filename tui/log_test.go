@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLog(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	tests := []struct {
+		name string
+		line string
+		want Log
+	}{
+		{
+			name: "timestamp",
+			line: "2024-01-02T03:04:05.6Z level=error msg=boom",
+			want: Log{Time: ts, Text: "level=error msg=boom", Level: LevelError},
+		},
+		{
+			name: "no timestamp",
+			line: "INFO listening on :8080",
+			want: Log{Text: "INFO listening on :8080", Level: LevelInfo},
+		},
+		{
+			name: "no space",
+			line: "2024-01-02T03:04:05.6Z",
+			want: Log{Text: "2024-01-02T03:04:05.6Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLog(tt.line)
+			if !got.Time.Equal(tt.want.Time) || got.Text != tt.want.Text || got.Level != tt.want.Level {
+				t.Errorf("ParseLog(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogs(t *testing.T) {
+	at := func(s int) time.Time {
+		return time.Date(2024, 1, 2, 3, 4, s, 0, time.UTC)
+	}
+	stamp := func(s int, text string) string {
+		return at(s).Format(time.RFC3339Nano) + " " + text
+	}
+
+	tests := []struct {
+		name      string
+		last      Log
+		lines     []string
+		want      []Log
+		continued bool
+	}{
+		{
+			name: "timestamped lines",
+			lines: []string{
+				stamp(1, "starting"),
+				stamp(2, "ERROR failed"),
+			},
+			want: []Log{
+				{Time: at(1), Text: "starting"},
+				{Time: at(2), Prev: at(1), Text: "ERROR failed", Level: LevelError},
+			},
+		},
+		{
+			name: "timestamped trace",
+			lines: []string{
+				stamp(1, "java.lang.IllegalStateException: boom"),
+				stamp(1, "\tat com.example.Main.run(Main.java:10)"),
+				stamp(2, "done"),
+			},
+			want: []Log{
+				{Time: at(1), Text: "java.lang.IllegalStateException: boom\n\tat com.example.Main.run(Main.java:10)"},
+				{Time: at(2), Prev: at(1), Text: "done"},
+			},
+		},
+		{
+			name: "trace split across calls",
+			last: Log{Time: at(1), Text: "java.lang.IllegalStateException: boom\n\tat com.example.Main.run(Main.java:10)"},
+			lines: []string{
+				stamp(1, "Caused by: java.io.IOException: closed"),
+				stamp(1, "\t... 3 more"),
+				stamp(2, "done"),
+			},
+			want: []Log{
+				{
+					Time: at(1),
+					Text: "java.lang.IllegalStateException: boom\n" +
+						"\tat com.example.Main.run(Main.java:10)\n" +
+						"Caused by: java.io.IOException: closed\n" +
+						"\t... 3 more",
+				},
+				{Time: at(2), Prev: at(1), Text: "done"},
+			},
+			continued: true,
+		},
+		{
+			name: "new entry after the last",
+			last: Log{Time: at(1), Text: "java.lang.IllegalStateException: boom"},
+			lines: []string{
+				stamp(2, "done"),
+			},
+			want: []Log{
+				{Time: at(2), Prev: at(1), Text: "done"},
+			},
+		},
+		{
+			name: "prev skips lines without a time",
+			last: Log{Time: at(1), Text: "starting"},
+			lines: []string{
+				"no timestamp",
+				stamp(3, "done"),
+			},
+			want: []Log{
+				{Prev: at(1), Text: "no timestamp"},
+				{Time: at(3), Prev: at(1), Text: "done"},
+			},
+		},
+		{
+			name: "blank lines are dropped",
+			lines: []string{
+				stamp(1, "starting"),
+				stamp(1, ""),
+				stamp(2, "done"),
+			},
+			want: []Log{
+				{Time: at(1), Text: "starting"},
+				{Time: at(2), Prev: at(1), Text: "done"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, continued := ParseLogs(tt.last, tt.lines)
+
+			got := make([]Log, len(entries))
+			for i, e := range entries {
+				got[i] = e.(Log)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLogs() = %+v, want %+v", got, tt.want)
+			}
+			if continued != tt.continued {
+				t.Errorf("continued = %v, want %v", continued, tt.continued)
+			}
+		})
+	}
+}
@@ -1,17 +1,58 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/list"
+import (
+	"fmt"
 
-type Namespace string
+	"github.com/charmbracelet/bubbles/list"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Namespace struct {
+	Name string
+	// Source says where a namespace came from when it wasn't listed by the
+	// cluster, e.g. "kubeconfig context"
+	Source string
+}
+
+const AllNamespacesTitle = "all namespaces"
+
+// NamespaceTitle names a namespace in titles, where metav1.NamespaceAll
+// would otherwise render as nothing
+func NamespaceTitle(namespace string) string {
+	if namespace == metav1.NamespaceAll {
+		return AllNamespacesTitle
+	}
+	return namespace
+}
+
+func (n Namespace) Title() string {
+	if n.Source == "" {
+		return NamespaceTitle(n.Name)
+	}
+	return fmt.Sprintf("%s (%s)", NamespaceTitle(n.Name), n.Source)
+}
 
 func (n Namespace) FilterValue() string {
-	return string(n)
+	return NamespaceTitle(n.Name)
 }
 
+// WrapNamespaces lists namespaces after an "all namespaces" entry
 func WrapNamespaces(namespaces []string) []list.Item {
-	wrapped := make([]list.Item, len(namespaces))
+	wrapped := make([]list.Item, len(namespaces)+1)
+	wrapped[0] = Namespace{Name: metav1.NamespaceAll}
 	for i, n := range namespaces {
-		wrapped[i] = Namespace(n)
+		wrapped[i+1] = Namespace{Name: n}
 	}
 	return wrapped
 }
+
+// NamespacePrompt opens a prompt to type a namespace name
+type NamespacePrompt struct{}
+
+func (NamespacePrompt) Title() string {
+	return "enter a namespace…"
+}
+
+func (NamespacePrompt) FilterValue() string {
+	return "enter a namespace"
+}
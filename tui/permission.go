@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/joshuasprow/log-viewer/k8s"
+)
+
+type PermissionCheck struct {
+	k8s.PermissionCheck
+}
+
+func (p PermissionCheck) Title() string {
+	icon := "✅"
+	if !p.Allowed {
+		icon = "🚫"
+	}
+	return icon + " " + p.Permission.String()
+}
+
+func (p PermissionCheck) Description() string {
+	return p.Reason
+}
+
+func (p PermissionCheck) FilterValue() string {
+	return p.Permission.String()
+}
+
+func WrapPermissionChecks(checks []k8s.PermissionCheck) []list.Item {
+	wrapped := make([]list.Item, len(checks))
+	for i, c := range checks {
+		wrapped[i] = PermissionCheck{c}
+	}
+	return wrapped
+}
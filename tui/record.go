@@ -0,0 +1,309 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+// Parsers force a log format for the containers they match
+var Parsers []pkg.ParserOverride
+
+// ParserFormat is the format the first matching parser forces for the
+// container, or "" to detect it
+func ParserFormat(c k8s.Container) string {
+	name := c.Namespace + "/" + c.Pod + "/" + c.Name
+
+	for _, p := range Parsers {
+		if ok, _ := path.Match(p.Match, name); ok {
+			return p.Format
+		}
+	}
+
+	return ""
+}
+
+type NodeKind int
+
+const (
+	ObjectNode NodeKind = iota
+	ArrayNode
+	StringNode
+	NumberNode
+	BoolNode
+	NullNode
+)
+
+// Node is a value of a parsed log record. objects keep their keys in the
+// order they were logged.
+type Node struct {
+	Key      string
+	Kind     NodeKind
+	Value    string
+	Children []Node
+}
+
+// String is the value as it would be filtered on or copied: strings
+// unquoted, everything else as JSON
+func (n Node) String() string {
+	switch n.Kind {
+	case ObjectNode, ArrayNode:
+		data, _ := json.Marshal(n.any())
+		return string(data)
+	default:
+		return n.Value
+	}
+}
+
+func (n Node) any() any {
+	switch n.Kind {
+	case ObjectNode:
+		// keys are sorted by json.Marshal, which is fine for copying
+		m := map[string]any{}
+		for _, c := range n.Children {
+			m[c.Key] = c.any()
+		}
+		return m
+	case ArrayNode:
+		a := make([]any, len(n.Children))
+		for i, c := range n.Children {
+			a[i] = c.any()
+		}
+		return a
+	case NumberNode:
+		return json.Number(n.Value)
+	case BoolNode:
+		return n.Value == "true"
+	case NullNode:
+		return nil
+	default:
+		return n.Value
+	}
+}
+
+// Field finds the node at a dotted path, e.g. "http.status"
+func (n Node) Field(path string) (Node, bool) {
+	for _, key := range strings.Split(path, ".") {
+		found := false
+
+		for _, c := range n.Children {
+			if c.Key == key {
+				n, found = c, true
+				break
+			}
+		}
+
+		if !found {
+			return Node{}, false
+		}
+	}
+
+	return n, true
+}
+
+// ParseRecord parses a JSON or logfmt entry into an object node. format
+// forces one of pkg.ParserFormats, "" detects it.
+func ParseRecord(text string, format string) (Node, bool) {
+	switch format {
+	case "json":
+		return parseJSONRecord(text)
+	case "logfmt":
+		return parseLogfmtRecord(text)
+	case "plain":
+		return Node{}, false
+	}
+
+	if n, ok := parseJSONRecord(text); ok {
+		return n, true
+	}
+	return parseLogfmtRecord(text)
+}
+
+func parseJSONRecord(text string) (Node, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") {
+		return Node{}, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+
+	n, err := decodeNode(dec)
+	if err != nil {
+		return Node{}, false
+	}
+
+	// trailing garbage means it wasn't a single record
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return Node{}, false
+	}
+
+	return n, true
+}
+
+func decodeNode(dec *json.Decoder) (Node, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return Node{}, err
+	}
+
+	switch t := t.(type) {
+	case json.Delim:
+		n := Node{Kind: ObjectNode}
+		if t == '[' {
+			n.Kind = ArrayNode
+		}
+
+		for i := 0; dec.More(); i++ {
+			key := strconv.Itoa(i)
+
+			if n.Kind == ObjectNode {
+				k, err := dec.Token()
+				if err != nil {
+					return Node{}, err
+				}
+				key = k.(string)
+			}
+
+			c, err := decodeNode(dec)
+			if err != nil {
+				return Node{}, err
+			}
+			c.Key = key
+
+			n.Children = append(n.Children, c)
+		}
+
+		// the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return Node{}, err
+		}
+
+		return n, nil
+	case string:
+		return Node{Kind: StringNode, Value: t}, nil
+	case json.Number:
+		return Node{Kind: NumberNode, Value: t.String()}, nil
+	case bool:
+		return Node{Kind: BoolNode, Value: strconv.FormatBool(t)}, nil
+	case nil:
+		return Node{Kind: NullNode, Value: "null"}, nil
+	default:
+		return Node{}, fmt.Errorf("unexpected token %v", t)
+	}
+}
+
+// parseLogfmtRecord parses key=value pairs. every word has to be a pair, so
+// text with a few pairs in it stays plain.
+func parseLogfmtRecord(text string) (Node, bool) {
+	n := Node{Kind: ObjectNode}
+	s := strings.TrimSpace(text)
+
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return r == '=' || unicode.IsSpace(r)
+		})
+		if i <= 0 || s[i] != '=' {
+			return Node{}, false
+		}
+
+		key := s[:i]
+		s = s[i+1:]
+
+		var value string
+
+		if strings.HasPrefix(s, `"`) {
+			v, rest, ok := unquotePrefix(s)
+			if !ok {
+				return Node{}, false
+			}
+			value, s = v, rest
+		} else {
+			end := strings.IndexFunc(s, unicode.IsSpace)
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+		}
+
+		n.Children = append(n.Children, Node{
+			Key:   key,
+			Kind:  StringNode,
+			Value: value,
+		})
+
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	}
+
+	return n, len(n.Children) > 0
+}
+
+// unquotePrefix unquotes the Go style quoted string s starts with
+func unquotePrefix(s string) (string, string, bool) {
+	escaped := false
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			v, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			return v, s[i+1:], true
+		}
+	}
+
+	return "", "", false
+}
+
+// FilterLogs is the list filter for the log entries of a container whose
+// records are parsed as format, "" to detect it. a "key=value" term keeps
+// the structured entries with that field value, other terms are matched
+// fuzzily.
+func FilterLogs(format string) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || key == "" || strings.ContainsFunc(key, unicode.IsSpace) {
+			return list.DefaultFilter(term, targets)
+		}
+
+		ranks := []list.Rank{}
+
+		for i, target := range targets {
+			if r, ok := ParseRecord(target, format); ok {
+				if f, ok := r.Field(key); ok {
+					if f.String() == value {
+						ranks = append(ranks, list.Rank{Index: i})
+					}
+					continue
+				}
+			}
+
+			// plain text can still carry pairs, e.g. "took=3ms done", and
+			// a term that isn't a field may be text, e.g. a url's query
+			if strings.Contains(target, term) {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+
+		return ranks
+	}
+}
+
+// FieldFilter is the filter term FilterLogs matches a field with
+func FieldFilter(path string, n Node) string {
+	return path + "=" + n.String()
+}
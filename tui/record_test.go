@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterLogs(t *testing.T) {
+	targets := []string{
+		`{"level":"error","req":{"path":"/api"}}`,
+		`{"level":"info","req":{"path":"/health"}}`,
+		`level=warn msg="retry=2"`,
+		`GET /search?q=go took 3ms`,
+		`plain line`,
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		term   string
+		want   []int
+	}{
+		{name: "field", term: "level=error", want: []int{0}},
+		{name: "nested field", term: "req.path=/health", want: []int{1}},
+		{name: "logfmt field", term: "msg=retry=2", want: []int{2}},
+		{name: "plain format", format: "plain", term: "msg=retry=2", want: []int{}},
+		{name: "plain format substring", format: "plain", term: `level=warn`, want: []int{2}},
+		{name: "not a field", term: "q=go", want: []int{3}},
+		{name: "no match", term: "level=debug", want: []int{}},
+		{name: "fuzzy", term: "plain", want: []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, r := range FilterLogs(tt.format)(tt.term, targets) {
+				got = append(got, r.Index)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterLogs(%q)(%q) = %v, want %v", tt.format, tt.term, got, tt.want)
+			}
+		})
+	}
+}
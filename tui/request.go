@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/joshuasprow/log-viewer/k8s"
+	"github.com/joshuasprow/log-viewer/pkg"
+)
+
+type RequestID uint64
+
+// Request asks the message handler to load the data for a view. Ctx belongs
+// to the view and is cancelled as soon as the user navigates away from it.
+type Request struct {
+	ID  RequestID
+	Ctx context.Context
+	Msg tea.Msg
+}
+
+type ItemsMsg struct {
+	RequestID RequestID
+	Items     []list.Item
+}
+
+type ErrorMsg struct {
+	RequestID RequestID
+	// Msg is the view message whose request failed
+	Msg tea.Msg
+	Err error
+}
+
+func (e ErrorMsg) Error() string {
+	return fmt.Sprintf("request %d: %v", e.RequestID, e.Err)
+}
+
+func (e ErrorMsg) Unwrap() error {
+	return e.Err
+}
+
+// Dispatcher runs requests in the background. Submit must never block, since
+// it's called from the Bubble Tea update loop.
+type Dispatcher interface {
+	Submit(req Request)
+	Stats() QueueStats
+}
+
+// LogSources opens the log sources views read from
+type LogSources interface {
+	// Pod reads the logs of a container from since, or the configured tail
+	// when it's zero. start is when its logging began, if known.
+	Pod(container k8s.Container, start time.Time, since time.Time) pkg.LogSource
+	// File reads a local log file and its rotated files. local.StdinPath
+	// stands for stdin.
+	File(path string) pkg.LogSource
+	// Replay plays a stream of the session being replayed back from since
+	Replay(stream int, since time.Time) pkg.LogSource
+	// Archived reads the archived logs of a container of a finished job
+	// from since, or all of them when it's zero
+	Archived(container k8s.Container, start time.Time, since time.Time) pkg.LogSource
+}
+
+type QueueEntry struct {
+	Msg        tea.Msg
+	RequestIDs []RequestID
+	Queued     time.Time
+	Started    time.Time
+	Finished   time.Time
+	Cancelled  bool
+	Err        error
+}
+
+type QueueStats struct {
+	Workers   int
+	Running   []QueueEntry
+	Queued    []QueueEntry
+	Recent    []QueueEntry
+	Completed int
+	Coalesced int
+	Cancelled int
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/joshuasprow/log-viewer/session"
+)
+
+// Stream is a stream of a recorded session
+type Stream struct {
+	session.Stream
+}
+
+func (s Stream) Title() string {
+	if s.Meta.Pod == "" {
+		return s.Meta.Container
+	}
+	return s.Meta.Pod + "/" + s.Meta.Container
+}
+
+func (s Stream) Description() string {
+	desc := fmt.Sprintf("%d lines", len(s.Lines))
+
+	if s.Meta.Cluster != "" {
+		desc += " · " + s.Meta.Cluster + "/" + s.Meta.Namespace
+	}
+
+	if n := len(s.Lines); n > 0 {
+		first, last := s.Lines[0].At, s.Lines[n-1].At
+		desc += fmt.Sprintf(" · from %s for %s", FormatTime(first), last.Sub(first).Round(time.Second))
+	}
+
+	return desc
+}
+
+func (s Stream) FilterValue() string {
+	return s.Title()
+}
+
+func WrapStreams(streams []session.Stream) []list.Item {
+	wrapped := make([]list.Item, len(streams))
+	for i, s := range streams {
+		wrapped[i] = Stream{s}
+	}
+	return wrapped
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/joshuasprow/log-viewer/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+type Theme struct {
+	Name string
+	// TitlePath colors the leading segments of a title path
+	TitlePath lipgloss.TerminalColor
+	// TitleCurrent colors the last segment of a title path
+	TitleCurrent lipgloss.TerminalColor
+	ListTitle    lipgloss.TerminalColor
+	Selected     lipgloss.TerminalColor
+	Muted        lipgloss.TerminalColor
+	Error        lipgloss.TerminalColor
+	// Added and Removed color the lines of a diff
+	Added   lipgloss.TerminalColor
+	Removed lipgloss.TerminalColor
+	// Warn and Info color the levels of the timeline, along with Error and
+	// Muted
+	Warn lipgloss.TerminalColor
+	Info lipgloss.TerminalColor
+}
+
+var ActiveTheme = DarkTheme()
+
+var themes = map[string]func() Theme{
+	"dark":          DarkTheme,
+	"light":         LightTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+func DarkTheme() Theme {
+	return Theme{
+		Name:         "dark",
+		TitlePath:    lipgloss.Color("#FF00FF"),
+		TitleCurrent: lipgloss.Color("#00FF00"),
+		ListTitle:    lipgloss.Color("205"),
+		Selected:     lipgloss.Color("170"),
+		Muted:        lipgloss.Color("244"),
+		Error:        lipgloss.Color("#FF0000"),
+		Added:        lipgloss.Color("#00D75F"),
+		Removed:      lipgloss.Color("#FF5F5F"),
+		Warn:         lipgloss.Color("#FFAF00"),
+		Info:         lipgloss.Color("#5FAFFF"),
+	}
+}
+
+func LightTheme() Theme {
+	return Theme{
+		Name:         "light",
+		TitlePath:    lipgloss.Color("#8700AF"),
+		TitleCurrent: lipgloss.Color("#005F00"),
+		ListTitle:    lipgloss.Color("125"),
+		Selected:     lipgloss.Color("91"),
+		Muted:        lipgloss.Color("240"),
+		Error:        lipgloss.Color("#AF0000"),
+		Added:        lipgloss.Color("#008700"),
+		Removed:      lipgloss.Color("#D70000"),
+		Warn:         lipgloss.Color("#AF5F00"),
+		Info:         lipgloss.Color("#005FAF"),
+	}
+}
+
+func HighContrastTheme() Theme {
+	return Theme{
+		Name:         "high-contrast",
+		TitlePath:    lipgloss.Color("#00FFFF"),
+		TitleCurrent: lipgloss.Color("#FFFF00"),
+		ListTitle:    lipgloss.Color("#FFFFFF"),
+		Selected:     lipgloss.Color("#FFFF00"),
+		Muted:        lipgloss.Color("#FFFFFF"),
+		Error:        lipgloss.Color("#FF5555"),
+		Added:        lipgloss.Color("#00FF00"),
+		Removed:      lipgloss.Color("#FF5555"),
+		Warn:         lipgloss.Color("#FFFF00"),
+		Info:         lipgloss.Color("#00FFFF"),
+	}
+}
+
+func NoColorTheme() Theme {
+	return Theme{
+		Name:         "no-color",
+		TitlePath:    lipgloss.NoColor{},
+		TitleCurrent: lipgloss.NoColor{},
+		ListTitle:    lipgloss.NoColor{},
+		Selected:     lipgloss.NoColor{},
+		Muted:        lipgloss.NoColor{},
+		Error:        lipgloss.NoColor{},
+		Added:        lipgloss.NoColor{},
+		Removed:      lipgloss.NoColor{},
+		Warn:         lipgloss.NoColor{},
+		Info:         lipgloss.NoColor{},
+	}
+}
+
+// LoadTheme returns a built-in theme by name, or reads a custom theme from
+// a YAML file when name looks like a path. NO_COLOR always wins.
+func LoadTheme(name string) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColorTheme(), nil
+	}
+
+	if name == "" {
+		return DarkTheme(), nil
+	}
+
+	if newTheme, ok := themes[name]; ok {
+		return newTheme(), nil
+	}
+
+	if !pkg.IsThemePath(name) {
+		return Theme{}, fmt.Errorf(
+			"unknown theme %q (want one of %s or a path to a theme file)",
+			name,
+			strings.Join(sortedKeys(themes), ", "),
+		)
+	}
+
+	return loadThemeFile(name)
+}
+
+type themeFile struct {
+	Base         string `yaml:"base"`
+	TitlePath    string `yaml:"titlePath"`
+	TitleCurrent string `yaml:"titleCurrent"`
+	ListTitle    string `yaml:"listTitle"`
+	Selected     string `yaml:"selected"`
+	Muted        string `yaml:"muted"`
+	Error        string `yaml:"error"`
+	Added        string `yaml:"added"`
+	Removed      string `yaml:"removed"`
+	Warn         string `yaml:"warn"`
+	Info         string `yaml:"info"`
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func loadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("read theme: %w", err)
+	}
+
+	tf := themeFile{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&tf); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	base := DarkTheme
+	if tf.Base != "" {
+		var ok bool
+		if base, ok = themes[tf.Base]; !ok {
+			return Theme{}, fmt.Errorf("%s: unknown base theme %q", path, tf.Base)
+		}
+	}
+
+	t := base()
+	t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	errs := []error{}
+
+	set := func(field string, value string, color *lipgloss.TerminalColor) {
+		if value == "" {
+			return
+		}
+
+		if n, err := strconv.Atoi(value); (err != nil || n < 0 || n > 255) &&
+			!hexColor.MatchString(value) {
+			errs = append(errs, fmt.Errorf(
+				"%s: %s: invalid color %q (want #RRGGBB or an ANSI color 0-255)",
+				path,
+				field,
+				value,
+			))
+			return
+		}
+
+		*color = lipgloss.Color(value)
+	}
+
+	set("titlePath", tf.TitlePath, &t.TitlePath)
+	set("titleCurrent", tf.TitleCurrent, &t.TitleCurrent)
+	set("listTitle", tf.ListTitle, &t.ListTitle)
+	set("selected", tf.Selected, &t.Selected)
+	set("muted", tf.Muted, &t.Muted)
+	set("error", tf.Error, &t.Error)
+	set("added", tf.Added, &t.Added)
+	set("removed", tf.Removed, &t.Removed)
+	set("warn", tf.Warn, &t.Warn)
+	set("info", tf.Info, &t.Info)
+
+	if err := errors.Join(errs...); err != nil {
+		return Theme{}, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	TimestampFormat = "2006-01-02T15:04:05"
+	// TimeZone is the zone times are shown in, except in UTC mode
+	TimeZone = time.Local
+	// GapThreshold flags log lines that came this long after the line
+	// before them
+	GapThreshold = time.Minute
+	TimeGutter   = GutterOff
+)
+
+// GutterMode is how the timestamps of log lines are shown in front of them
+type GutterMode int
+
+const (
+	GutterOff GutterMode = iota
+	GutterLocal
+	GutterUTC
+	GutterRelative
+	GutterDelta
+)
+
+func (g GutterMode) Next() GutterMode {
+	return (g + 1) % (GutterDelta + 1)
+}
+
+func (g GutterMode) String() string {
+	switch g {
+	case GutterLocal:
+		return "local"
+	case GutterUTC:
+		return "utc"
+	case GutterRelative:
+		return "relative"
+	case GutterDelta:
+		return "delta"
+	default:
+		return "off"
+	}
+}
+
+// FormatTime formats t in the configured time zone
+func FormatTime(t time.Time) string {
+	return t.In(TimeZone).Format(TimestampFormat)
+}
+
+// Ago formats how long ago t was, e.g. "3m ago"
+func Ago(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", max(int(d.Seconds()), 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// Delta formats the time between two lines, e.g. "+1.2s"
+func Delta(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return "+" + d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return "+" + d.Round(100*time.Millisecond).String()
+	default:
+		return "+" + d.Round(time.Second).String()
+	}
+}
+
+// timeLayouts are tried in order by ParseTime. layouts without a date are
+// on the day of now.
+var timeLayouts = []struct {
+	layout string
+	dated  bool
+}{
+	{time.RFC3339Nano, true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02T15:04:05", true},
+	{"2006-01-02 15:04", true},
+	{"2006-01-02T15:04", true},
+	{"15:04:05", false},
+	{"15:04", false},
+}
+
+// ParseTime reads a point in time: an absolute time ("02:14",
+// "2024-05-01 02:14:00"), a duration before now ("-15m", "15m ago") or
+// "start" for start. times of day that are still to come today mean
+// yesterday.
+func ParseTime(s string, now time.Time, start time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "start" {
+		if start.IsZero() {
+			return time.Time{}, errors.New("this log has no start time")
+		}
+		return start, nil
+	}
+
+	if d := strings.TrimSuffix(strings.TrimPrefix(s, "-"), " ago"); d != s {
+		ago, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration %q", d)
+		}
+		return now.Add(-ago), nil
+	}
+
+	now = now.In(TimeZone)
+
+	for _, l := range timeLayouts {
+		t, err := time.ParseInLocation(l.layout, s, TimeZone)
+		if err != nil {
+			continue
+		}
+
+		if l.dated {
+			return t, nil
+		}
+
+		t = time.Date(
+			now.Year(), now.Month(), now.Day(),
+			t.Hour(), t.Minute(), t.Second(), 0,
+			TimeZone,
+		)
+		if t.After(now) {
+			t = t.AddDate(0, 0, -1)
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"%q isn't a time (15:04, 2006-01-02 15:04:05), duration (-15m) or start",
+		s,
+	)
+}
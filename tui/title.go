@@ -10,15 +10,15 @@ func RenderTitle(path ...string) string {
 			title += " > "
 		}
 
-		color := "#FF00FF"
+		color := ActiveTheme.TitlePath
 
 		if i == len(path)-1 {
-			color = "#00FF00"
+			color = ActiveTheme.TitleCurrent
 		}
 
 		title += lipgloss.
 			NewStyle().
-			Foreground(lipgloss.Color(color)).
+			Foreground(color).
 			Render(p)
 	}
 
@@ -7,8 +7,11 @@ import (
 type ViewData struct {
 	Namespace        string
 	Api              Api
+	LabelSelector    string
+	FieldSelector    string
 	Container        k8s.Container
 	CronJob          k8s.CronJob
 	CronJobJob       k8s.Job
+	CronJobArchived  bool
 	CronJobContainer k8s.Container
 }
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/joshuasprow/log-viewer/k8s"
 )
 
@@ -11,12 +13,18 @@ type ApisViewMsg struct {
 }
 
 type ContainersViewMsg struct {
-	Namespace string
-	Api       Api
+	Namespace     string
+	Api           Api
+	LabelSelector string
+	FieldSelector string
+	// Compare picks a container to show next to this one in a split
+	Compare k8s.Container
 }
 
 type ContainerLogsViewMsg struct {
 	Container k8s.Container
+	// SinceTime loads the history from that time on, instead of the tail
+	SinceTime time.Time
 }
 
 type CronJobsViewMsg struct {
@@ -24,14 +32,74 @@ type CronJobsViewMsg struct {
 	Api       Api
 }
 
+// CronJobJobsViewMsg lists the jobs of a cron job. a set Compare picks the
+// job to diff it with.
 type CronJobJobsViewMsg struct {
 	CronJob k8s.CronJob
+	Compare Job
 }
 
+// CronJobContainersViewMsg lists the containers of a job. an archived job's
+// containers are the ones its logs were archived for.
 type CronJobContainersViewMsg struct {
-	Job k8s.Job
+	Job      k8s.Job
+	Archived bool
 }
 
 type CronJobLogsViewMsg struct {
 	Container k8s.Container
+	SinceTime time.Time
+	// Archived reads the logs from the archive instead of the pod
+	Archived bool
+}
+
+// JobDiffViewMsg compares the logs of two runs of a cron job, the older on
+// the left
+type JobDiffViewMsg struct {
+	CronJob k8s.CronJob
+	Left    Job
+	Right   Job
+}
+
+type PermissionsViewMsg struct {
+	Namespace string
+}
+
+// BookmarksViewMsg lists the bookmarks of a namespace. Local lists the
+// ones of local files instead, and Namespace is the one to go back to.
+type BookmarksViewMsg struct {
+	Namespace string
+	Local     bool
+}
+
+type NamespacePromptViewMsg struct{}
+
+type SelectorPromptViewMsg struct {
+	Containers ContainersViewMsg
+}
+
+type SplitViewMsg struct {
+	Left  k8s.Container
+	Right k8s.Container
+}
+
+// FilesViewMsg lists the local log files. Namespace is the one to go back
+// to.
+type FilesViewMsg struct {
+	Namespace string
+}
+
+type FileLogsViewMsg struct {
+	Namespace string
+	Path      string
+	// SinceTime selects the first line logged at or after it, once read
+	SinceTime time.Time
+}
+
+// SessionViewMsg lists the streams of the session being replayed
+type SessionViewMsg struct{}
+
+type ReplayLogsViewMsg struct {
+	Stream    int
+	SinceTime time.Time
 }